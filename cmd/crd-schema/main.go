@@ -0,0 +1,236 @@
+// Command crd-schema writes the OpenAPI v3 validation schema of the
+// threekit.com custom resources into their CustomResourceDefinitions under
+// deploy/. The schema is built from the definitions generated by openapi-gen,
+// with every reference inlined since CRD schemas may not use $ref. Run it from
+// the repository root, after openapi-gen, as tmp/codegen/update-generated.sh
+// does. The Workflow CRD serves several versions, each with its own schema.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1"
+	"github.com/ghodss/yaml"
+	"github.com/go-openapi/spec"
+	"k8s.io/kube-openapi/pkg/common"
+)
+
+const (
+	definitionPrefix = "#/definitions/"
+	typesPackage     = "github.com/Ziyang2go/workflowop/pkg/apis/threekit/"
+)
+
+// version is a version served by a CRD.
+type version struct {
+	name    string
+	storage bool
+}
+
+var crds = []struct {
+	kind string
+	file string
+	// versions lists the served versions, the preferred one first. A CRD
+	// with a single version gets a top-level validation schema.
+	versions []version
+}{
+	{"Workflow", "deploy/crd.yaml", []version{{"v1beta1", true}, {"v1alpha", false}}},
+	{"WorkflowTemplate", "deploy/workflowtemplate-crd.yaml", []version{{"v1alpha", true}}},
+	{"CronWorkflow", "deploy/cronworkflow-crd.yaml", []version{{"v1alpha", true}}},
+}
+
+// external are the schemas of the types defined outside of this repository. A
+// RawExtension is any JSON value.
+var external = map[string]map[string]interface{}{
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Time":    {"type": "string", "format": "date-time"},
+	"k8s.io/apimachinery/pkg/runtime.RawExtension": {},
+	"k8s.io/api/core/v1.ObjectReference":           {"type": "object"},
+}
+
+func main() {
+	definitions, err := loadDefinitions()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, crd := range crds {
+		if err := writeSchema(crd.file, crd.kind, crd.versions, definitions); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", crd.file, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// loadDefinitions returns the JSON form of the generated definitions by
+// type name.
+func loadDefinitions() (map[string]map[string]interface{}, error) {
+	ref := func(path string) spec.Ref {
+		return spec.MustCreateRef(definitionPrefix + path)
+	}
+	definitions := make(map[string]map[string]interface{})
+	for _, generated := range []map[string]common.OpenAPIDefinition{
+		v1alpha.GetOpenAPIDefinitions(ref),
+		v1beta1.GetOpenAPIDefinitions(ref),
+	} {
+		for name, definition := range generated {
+			data, err := json.Marshal(definition.Schema)
+			if err != nil {
+				return nil, err
+			}
+			var schema map[string]interface{}
+			if err := json.Unmarshal(data, &schema); err != nil {
+				return nil, err
+			}
+			definitions[name] = schema
+		}
+	}
+	return definitions, nil
+}
+
+// writeSchema replaces the validation and versions of the CRD in file.
+func writeSchema(file, kind string, versions []version, definitions map[string]map[string]interface{}) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var lines []string
+	skipping := false
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if line == "  validation:" || line == "  versions:" {
+			skipping = true
+			continue
+		}
+		if skipping && (strings.HasPrefix(line, "   ") || strings.HasPrefix(line, "  - ")) {
+			continue
+		}
+		skipping = false
+		lines = append(lines, line)
+	}
+
+	if len(versions) == 1 {
+		schema, err := versionSchema(kind, versions[0].name, definitions)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, "  validation:", "    openAPIV3Schema:")
+		lines = append(lines, indent(schema, "      ")...)
+	} else {
+		lines = append(lines, "  versions:")
+		for _, v := range versions {
+			schema, err := versionSchema(kind, v.name, definitions)
+			if err != nil {
+				return err
+			}
+			lines = append(lines,
+				"  - name: "+v.name,
+				"    served: true",
+				fmt.Sprintf("    storage: %t", v.storage),
+				"    schema:",
+				"      openAPIV3Schema:")
+			lines = append(lines, indent(schema, "        ")...)
+		}
+	}
+	return ioutil.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// versionSchema returns the schema of kind at version as YAML.
+func versionSchema(kind, version string, definitions map[string]map[string]interface{}) (string, error) {
+	root, found := definitions[typesPackage+version+"."+kind]
+	if !found {
+		return "", fmt.Errorf("no OpenAPI definition for %s/%s", version, kind)
+	}
+	// The API server validates metadata itself.
+	root = withoutProperty(root, "metadata")
+	schema, err := inline(root, definitions)
+	if err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(schema)
+	return string(out), err
+}
+
+func indent(text, prefix string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		lines = append(lines, prefix+line)
+	}
+	return lines
+}
+
+// withoutProperty returns a copy of schema without the named property.
+func withoutProperty(schema map[string]interface{}, name string) map[string]interface{} {
+	result := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		result[key] = value
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		kept := make(map[string]interface{}, len(properties))
+		for key, value := range properties {
+			if key != name {
+				kept[key] = value
+			}
+		}
+		result["properties"] = kept
+	}
+	if required, ok := schema["required"].([]interface{}); ok {
+		var kept []interface{}
+		for _, value := range required {
+			if value != name {
+				kept = append(kept, value)
+			}
+		}
+		result["required"] = kept
+		if len(kept) == 0 {
+			delete(result, "required")
+		}
+	}
+	return result
+}
+
+// inline returns schema with every $ref replaced by the schema it refers to.
+func inline(schema map[string]interface{}, definitions map[string]map[string]interface{}) (map[string]interface{}, error) {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, definitionPrefix)
+		target, found := definitions[name]
+		if !found {
+			if target, found = external[name]; !found {
+				return nil, fmt.Errorf("no OpenAPI definition for %s", name)
+			}
+		}
+		resolved, err := inline(target, definitions)
+		if err != nil {
+			return nil, err
+		}
+		if description, ok := schema["description"]; ok {
+			resolved["description"] = description
+		}
+		return resolved, nil
+	}
+	result := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		result[key] = value
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		inlined := make(map[string]interface{}, len(properties))
+		for name, property := range properties {
+			var err error
+			if inlined[name], err = inline(property.(map[string]interface{}), definitions); err != nil {
+				return nil, err
+			}
+		}
+		result["properties"] = inlined
+	}
+	for _, key := range []string{"items", "additionalProperties"} {
+		if nested, ok := schema[key].(map[string]interface{}); ok {
+			var err error
+			if result[key], err = inline(nested, definitions); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+// Command workflow-migrate moves the stored Workflows to threekit.com/v1beta1.
+// Once the Workflow CRD stores v1beta1, Workflows written before keep their
+// stored v1alpha form until they are written again. The command rewrites every
+// Workflow, which the API server stores as v1beta1 through the conversion
+// webhook, and then drops v1alpha from the storedVersions of the CRD, so that
+// v1alpha can eventually stop being served.
+//
+// It runs out of cluster with -kubeconfig or KUBERNETES_CONFIG, or in cluster
+// otherwise.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1"
+	"github.com/Ziyang2go/workflowop/pkg/webhook"
+	"github.com/operator-framework/operator-sdk/pkg/k8sclient"
+	k8sutil "github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
+	"github.com/sirupsen/logrus"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func main() {
+	kubeconfig := flag.String("kubeconfig", "", "path to the kubeconfig, in cluster config is used when empty")
+	namespace := flag.String("namespace", "", "namespace of the workflows to migrate, all namespaces when empty")
+	dryRun := flag.Bool("dry-run", false, "list the workflows to migrate without writing them")
+	flag.Parse()
+	if *kubeconfig != "" {
+		os.Setenv(k8sutil.KubeConfigEnvVar, *kubeconfig)
+	}
+
+	migrated, err := migrateWorkflows(*namespace, *dryRun)
+	if err != nil {
+		logrus.Fatalf("failed to migrate workflows: %v", err)
+	}
+	logrus.Infof("Migrated %d workflows to %s", migrated, v1beta1.SchemeGroupVersion)
+	if *dryRun || *namespace != "" {
+		// Workflows of other namespaces may still be stored as v1alpha.
+		return
+	}
+	if err := setStoredVersions(); err != nil {
+		logrus.Fatalf("failed to update CRD %s: %v", webhook.WorkflowCRD, err)
+	}
+	logrus.Infof("CRD %s stores %s only", webhook.WorkflowCRD, v1beta1.SchemeGroupVersion.Version)
+}
+
+// migrateWorkflows writes every Workflow of namespace back unchanged and
+// returns how many were written.
+func migrateWorkflows(namespace string, dryRun bool) (int, error) {
+	client, _, err := k8sclient.GetResourceClient(v1beta1.SchemeGroupVersion.String(), "Workflow", namespace)
+	if err != nil {
+		return 0, err
+	}
+	list, err := client.List(metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	for i := range list.Items {
+		wf := &list.Items[i]
+		if dryRun {
+			fmt.Printf("%s/%s\n", wf.GetNamespace(), wf.GetName())
+			continue
+		}
+		if err := migrateWorkflow(wf); err != nil {
+			return migrated, fmt.Errorf("workflow %s/%s: %v", wf.GetNamespace(), wf.GetName(), err)
+		}
+		migrated++
+	}
+	return migrated, nil
+}
+
+// migrateWorkflow writes wf back, getting it again when the operator updated
+// it in the meantime. Workflows deleted in the meantime need no migration.
+func migrateWorkflow(wf *unstructured.Unstructured) error {
+	client, _, err := k8sclient.GetResourceClient(v1beta1.SchemeGroupVersion.String(), "Workflow", wf.GetNamespace())
+	if err != nil {
+		return err
+	}
+	for {
+		_, err := client.Update(wf)
+		switch {
+		case err == nil, kubeerr.IsNotFound(err):
+			return nil
+		case !kubeerr.IsConflict(err):
+			return err
+		}
+		if wf, err = client.Get(wf.GetName(), metav1.GetOptions{}); err != nil {
+			if kubeerr.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// setStoredVersions records that every Workflow is stored as v1beta1.
+func setStoredVersions() error {
+	client, _, err := k8sclient.GetResourceClient("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "")
+	if err != nil {
+		return err
+	}
+	crd, err := client.Get(webhook.WorkflowCRD, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	storedVersions := []string{v1beta1.SchemeGroupVersion.Version}
+	if err := unstructured.SetNestedStringSlice(crd.Object, storedVersions, "status", "storedVersions"); err != nil {
+		return err
+	}
+	_, err = client.UpdateStatus(crd)
+	return err
+}
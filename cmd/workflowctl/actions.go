@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var cancelCommand = &command{
+	usage: "cancel WORKFLOW",
+	short: "Cancel a workflow: its running jobs are deleted and the rest never run",
+	run: func(o *options, args []string) error {
+		if err := exactArgs(args, "WORKFLOW"); err != nil {
+			return err
+		}
+		client, err := o.workflows()
+		if err != nil {
+			return err
+		}
+		wf, err := client.Patch(args[0], types.MergePatchType, []byte(`{"spec":{"cancel":true}}`))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.out, "workflow %s/%s cancelled\n", wf.Namespace, wf.Name)
+		return nil
+	},
+}
+
+var retryCommand = &command{
+	usage: "retry WORKFLOW",
+	short: "Submit a finished workflow again, as a new workflow",
+	flags: outputFlag,
+	run: func(o *options, args []string) error {
+		if err := exactArgs(args, "WORKFLOW"); err != nil {
+			return err
+		}
+		client, err := o.workflows()
+		if err != nil {
+			return err
+		}
+		wf, err := client.Get(args[0], metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !phase(wf).Finished() {
+			return fmt.Errorf("workflow %s is %s, only finished workflows can be retried", wf.Name, phase(wf))
+		}
+		return create(o, retryWorkflow(wf))
+	},
+}
+
+// retryWorkflow returns a new workflow that runs the jobs of wf again with
+// the same arguments. A workflow created from a template keeps the jobs it
+// was admitted with, rather than those of the current template, unless it
+// failed before the template was applied.
+func retryWorkflow(wf *v1alpha.Workflow) *v1alpha.Workflow {
+	retry := &v1alpha.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: wf.Name + "-",
+			Namespace:    wf.Namespace,
+			Labels:       make(map[string]string),
+			Annotations:  make(map[string]string),
+		},
+		Inputs: *wf.Inputs.DeepCopy(),
+		Spec:   *wf.Spec.DeepCopy(),
+	}
+	for key, value := range wf.Labels {
+		if key != v1alpha.CronWorkflowLabel {
+			retry.Labels[key] = value
+		}
+	}
+	for key, value := range wf.Annotations {
+		if key != v1alpha.WorkflowTemplateAnnotation {
+			retry.Annotations[key] = value
+		}
+	}
+	retry.Spec.JobBatch = nil
+	retry.Spec.Cancel = false
+	if len(retry.Inputs.Jobs) > 0 {
+		retry.Spec.WorkflowTemplateRef = nil
+	}
+	return retry
+}
+
+var deleteCommand = &command{
+	usage: "delete WORKFLOW",
+	short: "Delete a workflow together with its jobs",
+	run: func(o *options, args []string) error {
+		if err := exactArgs(args, "WORKFLOW"); err != nil {
+			return err
+		}
+		client, err := o.workflows()
+		if err != nil {
+			return err
+		}
+		propagation := metav1.DeletePropagationBackground
+		if err := client.Delete(args[0], &metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.out, "workflow %s/%s deleted\n", o.namespace, args[0])
+		return nil
+	},
+}
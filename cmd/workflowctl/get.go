@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	operator "github.com/Ziyang2go/workflowop/pkg/workflow"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var getCommand = &command{
+	usage: "get WORKFLOW",
+	short: "Show a workflow and the tree of its jobs",
+	flags: outputFlag,
+	run: func(o *options, args []string) error {
+		if err := exactArgs(args, "WORKFLOW"); err != nil {
+			return err
+		}
+		client, err := o.workflows()
+		if err != nil {
+			return err
+		}
+		wf, err := client.Get(args[0], metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if o.output != outputTable {
+			wf.APIVersion = v1alpha.SchemeGroupVersion.String()
+			wf.Kind = "Workflow"
+			return o.print(wf)
+		}
+		printWorkflow(o.out, wf)
+		return nil
+	},
+}
+
+// printWorkflow describes wf followed by its jobs in dependency order. The
+// children of a fan-out job are shown below it.
+func printWorkflow(out io.Writer, wf *v1alpha.Workflow) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", wf.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", wf.Namespace)
+	if org := wf.Labels[v1alpha.OrganizationLabel]; org != "" {
+		fmt.Fprintf(w, "Organization:\t%s\n", org)
+	}
+	fmt.Fprintf(w, "Status:\t%s\n", phase(wf))
+	if wf.Status.StartedAt != nil {
+		fmt.Fprintf(w, "Started:\t%s\n", wf.Status.StartedAt.Format(time.RFC3339))
+	}
+	if wf.Status.FinishedAt != nil {
+		fmt.Fprintf(w, "Finished:\t%s\n", wf.Status.FinishedAt.Format(time.RFC3339))
+	}
+	for _, param := range wf.Spec.Arguments.Parameters {
+		value := "<required>"
+		if param.Value != nil {
+			value = *param.Value
+		} else if param.Default != nil {
+			value = *param.Default
+		}
+		fmt.Fprintf(w, "Parameter:\t%s=%s\n", param.Name, value)
+	}
+	for _, condition := range wf.Status.Conditions {
+		if condition.Status != "True" {
+			continue
+		}
+		fmt.Fprintf(w, "Condition:\t%s (%s) %s\n", condition.Type, condition.Reason, condition.Message)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tSTATUS\tATTEMPTS\tDURATION\tBATCH JOB\tMESSAGE")
+	jobs := operator.WorkflowJobs(wf)
+	if sorted, err := operator.SortJobs(jobs); err == nil {
+		jobs = sorted
+	}
+	for _, job := range jobs {
+		printJob(w, wf, job.Name, job.Name)
+		if !job.FanOut() {
+			continue
+		}
+		fanOut := wf.Status.FanOut[job.Name]
+		for i := 0; i < int(fanOut.Total); i++ {
+			branch := " ├─ "
+			if i == int(fanOut.Total)-1 {
+				branch = " └─ "
+			}
+			name := job.Name + "-" + strconv.Itoa(i)
+			printJob(w, wf, name, branch+name)
+		}
+	}
+	w.Flush()
+}
+
+func printJob(w io.Writer, wf *v1alpha.Workflow, name, label string) {
+	status := wf.Status.JobStatus[name]
+	if status == "" {
+		status = v1alpha.JobPending
+	}
+	attempts, duration, batchName, message := "-", "-", "-", ""
+	if batch := wf.Status.JobBatch[name]; batch != nil {
+		if batch.Name != "" {
+			batchName = batch.Name
+		}
+		if n := len(batch.Attempts); n > 0 || batch.StartedAt != nil {
+			if !status.Finished() {
+				// The current attempt is not recorded until it finished.
+				n++
+			}
+			attempts = strconv.Itoa(n)
+		}
+		if batch.StartedAt != nil {
+			end := time.Now()
+			if batch.FinishedAt != nil {
+				end = batch.FinishedAt.Time
+			}
+			duration = shortDuration(end.Sub(batch.StartedAt.Time))
+		}
+		message = batch.Message
+		if message == "" && batch.Reason != "" {
+			message = batch.Reason
+		}
+		if status == v1alpha.JobRetrying && batch.RetryAfter != nil {
+			message = "retrying in " + shortDuration(time.Until(batch.RetryAfter.Time))
+		}
+	}
+	if fanOut, found := wf.Status.FanOut[name]; found {
+		message = strings.TrimSpace(fmt.Sprintf("%d/%d succeeded, %d failed, %d running %s",
+			fanOut.Succeeded, fanOut.Total, fanOut.Failed, fanOut.Running, fanOut.Message))
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", label, status, attempts, duration, batchName, message)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// filter selects workflows by phase and organization.
+type filter struct {
+	phase        string
+	organization string
+}
+
+func (f *filter) flags(fs *flag.FlagSet) {
+	fs.StringVar(&f.phase, "phase", "", "only workflows in this phase, e.g. working or failed")
+	fs.StringVar(&f.organization, "org", "", "only workflows of this organization")
+}
+
+// listOptions selects the organization on the server. Phases are matched by
+// the client since custom resources cannot be selected by status fields.
+func (f *filter) listOptions() metav1.ListOptions {
+	opts := metav1.ListOptions{}
+	if f.organization != "" {
+		opts.LabelSelector = labels.SelectorFromSet(labels.Set{v1alpha.OrganizationLabel: f.organization}).String()
+	}
+	return opts
+}
+
+func (f *filter) matches(wf *v1alpha.Workflow) bool {
+	return f.phase == "" || string(phase(wf)) == f.phase
+}
+
+var listFilter filter
+
+var listCommand = &command{
+	usage: "list [-phase PHASE] [-org ORG]",
+	short: "List workflows, newest first",
+	flags: func(fs *flag.FlagSet, o *options) {
+		outputFlag(fs, o)
+		listFilter.flags(fs)
+	},
+	run: func(o *options, args []string) error {
+		if err := exactArgs(args); err != nil {
+			return err
+		}
+		client, err := o.workflows()
+		if err != nil {
+			return err
+		}
+		list, err := client.List(listFilter.listOptions())
+		if err != nil {
+			return err
+		}
+		items := list.Items[:0]
+		for i := range list.Items {
+			if listFilter.matches(&list.Items[i]) {
+				items = append(items, list.Items[i])
+			}
+		}
+		list.Items = items
+		sort.Slice(list.Items, func(i, j int) bool {
+			return list.Items[j].CreationTimestamp.Before(&list.Items[i].CreationTimestamp)
+		})
+		if o.output != outputTable {
+			list.APIVersion = v1alpha.SchemeGroupVersion.String()
+			list.Kind = "WorkflowList"
+			return o.print(list)
+		}
+		w := newTable(o.out)
+		for i := range list.Items {
+			printRow(w, &list.Items[i])
+		}
+		return w.Flush()
+	},
+}
+
+var watchFilter filter
+
+var watchCommand = &command{
+	usage: "watch [-phase PHASE] [-org ORG] [WORKFLOW]",
+	short: "Print workflows as they change, until the named workflow finishes",
+	flags: func(fs *flag.FlagSet, o *options) {
+		outputFlag(fs, o)
+		watchFilter.flags(fs)
+	},
+	run: func(o *options, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("expected at most one WORKFLOW, got %d arguments", len(args))
+		}
+		client, err := o.workflows()
+		if err != nil {
+			return err
+		}
+		opts := watchFilter.listOptions()
+		if len(args) == 1 {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", args[0]).String()
+		}
+		// List first, so that the current state is printed and the watch
+		// starts where the list ended.
+		list, err := client.List(opts)
+		if err != nil {
+			return err
+		}
+		if len(args) == 1 && len(list.Items) == 0 {
+			return fmt.Errorf("workflow %s not found", args[0])
+		}
+		w := newTable(o.out)
+		show := func(wf *v1alpha.Workflow) error {
+			if !watchFilter.matches(wf) {
+				return nil
+			}
+			if o.output != outputTable {
+				return o.print(wf)
+			}
+			printRow(w, wf)
+			return w.Flush()
+		}
+		for i := range list.Items {
+			if err := show(&list.Items[i]); err != nil {
+				return err
+			}
+			if len(args) == 1 && phase(&list.Items[i]).Finished() {
+				return nil
+			}
+		}
+		opts.ResourceVersion = list.ResourceVersion
+		for {
+			watcher, err := client.Watch(opts)
+			if err != nil {
+				return err
+			}
+			for event := range watcher.ResultChan() {
+				if event.Type == watch.Error {
+					watcher.Stop()
+					return fmt.Errorf("watch failed: %v", event.Object)
+				}
+				wf, ok := event.Object.(*v1alpha.Workflow)
+				if !ok {
+					continue
+				}
+				opts.ResourceVersion = wf.ResourceVersion
+				if event.Type == watch.Deleted {
+					if len(args) == 1 {
+						watcher.Stop()
+						return fmt.Errorf("workflow %s was deleted", wf.Name)
+					}
+					continue
+				}
+				if err := show(wf); err != nil {
+					watcher.Stop()
+					return err
+				}
+				if len(args) == 1 && phase(wf).Finished() {
+					watcher.Stop()
+					return nil
+				}
+			}
+			// The server ends watches after a while; carry on from the
+			// last version seen.
+		}
+	},
+}
+
+// phase returns the phase of wf, pending when the operator did not set one.
+func phase(wf *v1alpha.Workflow) v1alpha.WorkflowPhase {
+	if wf.Status.Status == "" {
+		return v1alpha.WorkflowPending
+	}
+	return wf.Status.Status
+}
+
+func newTable(out io.Writer) *tabwriter.Writer {
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tORG\tSTATUS\tJOBS\tDURATION\tAGE")
+	return w
+}
+
+// printRow prints wf with how many of its jobs succeeded and how long it
+// ran so far.
+func printRow(w io.Writer, wf *v1alpha.Workflow) {
+	// Fan-out jobs are counted by their children.
+	succeeded, total := 0, 0
+	for name, status := range wf.Status.JobStatus {
+		if _, found := wf.Status.FanOut[name]; found {
+			continue
+		}
+		total++
+		if status == v1alpha.JobOK || status == v1alpha.JobSkipped {
+			succeeded++
+		}
+	}
+	org := wf.Labels[v1alpha.OrganizationLabel]
+	if org == "" {
+		org = "<none>"
+	}
+	duration := "-"
+	if wf.Status.StartedAt != nil {
+		end := time.Now()
+		if wf.Status.FinishedAt != nil {
+			end = wf.Status.FinishedAt.Time
+		}
+		duration = shortDuration(end.Sub(wf.Status.StartedAt.Time))
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%s\t%s\n", wf.Name, org, phase(wf), succeeded, total,
+		duration, shortDuration(time.Since(wf.CreationTimestamp.Time)))
+}
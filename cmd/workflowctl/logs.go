@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var logsFollow bool
+
+var logsCommand = &command{
+	usage: "logs [-f] WORKFLOW JOB",
+	short: "Print the log of a job of a workflow",
+	flags: func(fs *flag.FlagSet, o *options) {
+		fs.BoolVar(&logsFollow, "f", false, "follow the log of a running job")
+	},
+	run: func(o *options, args []string) error {
+		if err := exactArgs(args, "WORKFLOW", "JOB"); err != nil {
+			return err
+		}
+		client, err := o.workflows()
+		if err != nil {
+			return err
+		}
+		wf, err := client.Get(args[0], metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		batch := wf.Status.JobBatch[args[1]]
+		if batch == nil || batch.Name == "" {
+			if _, found := wf.Status.JobStatus[args[1]]; !found {
+				return fmt.Errorf("workflow %s has no job %s", wf.Name, args[1])
+			}
+			return fmt.Errorf("job %s of workflow %s has not started", args[1], wf.Name)
+		}
+		kube, err := o.kubeClient()
+		if err != nil {
+			return err
+		}
+		// The most recent pod of the batch Job of the current attempt. Its
+		// pods are gone once the operator cleaned the job up, the log the
+		// operator kept is printed then.
+		pods, err := kube.CoreV1().Pods(wf.Namespace).List(metav1.ListOptions{LabelSelector: "job-name=" + batch.Name})
+		if err != nil {
+			return err
+		}
+		var latest *corev1.Pod
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if latest == nil || latest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+				latest = pod
+			}
+		}
+		if latest == nil {
+			if batch.LogURL != "" {
+				fmt.Fprintf(os.Stderr, "Job %s was cleaned up, the complete log is archived at %s\n", batch.Name, batch.LogURL)
+			}
+			_, err := io.WriteString(o.out, batch.Logs)
+			return err
+		}
+		logOptions := &corev1.PodLogOptions{Follow: logsFollow}
+		if len(latest.Spec.Containers) > 0 {
+			logOptions.Container = latest.Spec.Containers[0].Name
+		}
+		stream, err := kube.CoreV1().Pods(wf.Namespace).GetLogs(latest.Name, logOptions).Stream()
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+		_, err = io.Copy(o.out, stream)
+		return err
+	},
+}
@@ -0,0 +1,193 @@
+// Command workflowctl submits and inspects Workflows.
+//
+//	workflowctl submit [-p name=value]... FILE
+//	workflowctl list [-phase PHASE] [-org ORG]
+//	workflowctl get WORKFLOW
+//	workflowctl watch [WORKFLOW]
+//	workflowctl logs [-f] WORKFLOW JOB
+//	workflowctl cancel WORKFLOW
+//	workflowctl retry WORKFLOW
+//	workflowctl delete WORKFLOW
+//
+// Every command takes -kubeconfig, -namespace and, when it prints workflows,
+// -o table|json|yaml. The kubeconfig defaults to KUBERNETES_CONFIG, then to
+// KUBECONFIG and ~/.kube/config.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/client/clientset/versioned"
+	threekitv1alpha "github.com/Ziyang2go/workflowop/pkg/client/clientset/versioned/typed/threekit/v1alpha"
+	"github.com/ghodss/yaml"
+	k8sutil "github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// command is a workflowctl subcommand. run gets the arguments left after
+// the flags of the command.
+type command struct {
+	usage string
+	short string
+	flags func(*flag.FlagSet, *options)
+	run   func(*options, []string) error
+}
+
+var commands = map[string]*command{
+	"submit": submitCommand,
+	"list":   listCommand,
+	"get":    getCommand,
+	"watch":  watchCommand,
+	"logs":   logsCommand,
+	"cancel": cancelCommand,
+	"retry":  retryCommand,
+	"delete": deleteCommand,
+}
+
+var commandOrder = []string{"submit", "list", "get", "watch", "logs", "cancel", "retry", "delete"}
+
+// options holds the flags shared by the commands and what they connect to.
+type options struct {
+	kubeconfig string
+	namespace  string
+	output     string
+	out        io.Writer
+
+	config *rest.Config
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		usage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	cmd := commands[name]
+	o := &options{out: os.Stdout}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.StringVar(&o.kubeconfig, "kubeconfig", os.Getenv(k8sutil.KubeConfigEnvVar), "path to the kubeconfig")
+	fs.StringVar(&o.namespace, "namespace", "", "namespace of the workflows, the namespace of the kubeconfig context when empty")
+	if cmd.flags != nil {
+		cmd.flags(fs, o)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: workflowctl %s\n\n%s\n\n", cmd.usage, cmd.short)
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[2:])
+	if err := o.complete(); err != nil {
+		fmt.Fprintf(os.Stderr, "workflowctl %s: %v\n", name, err)
+		os.Exit(1)
+	}
+	if err := cmd.run(o, fs.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "workflowctl %s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: workflowctl COMMAND [flags] [args]\n\nCommands:")
+	for _, name := range commandOrder {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].short)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun workflowctl COMMAND -h for the flags of a command.")
+}
+
+// outputFlag adds the -o flag to the commands that print workflows.
+func outputFlag(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.output, "o", outputTable, "output format: table, json or yaml")
+}
+
+// complete loads the kubeconfig and settles the namespace.
+func (o *options) complete() error {
+	switch o.output {
+	case "", outputTable, outputJSON, outputYAML:
+	default:
+		return fmt.Errorf("unknown output format %q", o.output)
+	}
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{})
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+	o.config = config
+	if o.namespace == "" {
+		if o.namespace, _, err = clientConfig.Namespace(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *options) workflows() (threekitv1alpha.WorkflowInterface, error) {
+	client, err := versioned.NewForConfig(o.config)
+	if err != nil {
+		return nil, err
+	}
+	return client.ThreekitV1alpha().Workflows(o.namespace), nil
+}
+
+func (o *options) kubeClient() (kubernetes.Interface, error) {
+	return kubernetes.NewForConfig(o.config)
+}
+
+// print writes obj as JSON or YAML. Tables are printed by the commands.
+func (o *options) print(obj interface{}) error {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+	if o.output == outputYAML {
+		if data, err = yaml.JSONToYAML(data); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(o.out, strings.TrimRight(string(data), "\n"))
+	return err
+}
+
+// exactArgs fails unless args holds one argument for each of names.
+func exactArgs(args []string, names ...string) error {
+	if len(names) == 0 && len(args) > 0 {
+		return fmt.Errorf("expected no arguments, got %d", len(args))
+	}
+	if len(args) != len(names) {
+		return fmt.Errorf("expected %s, got %d arguments", strings.Join(names, " and "), len(args))
+	}
+	return nil
+}
+
+// shortDuration formats d the way kubectl prints ages: in its largest unit
+// or two.
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < 0:
+		return "0s"
+	case d < 2*time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < 10*time.Minute:
+		return fmt.Sprintf("%dm%ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 3*time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
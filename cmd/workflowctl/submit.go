@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1"
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// parameters collects the name=value pairs of repeated -p flags.
+type parameters []v1alpha.Parameter
+
+func (p *parameters) String() string {
+	pairs := make([]string, 0, len(*p))
+	for _, param := range *p {
+		pairs = append(pairs, param.Name+"="+*param.Value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (p *parameters) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("expected name=value, got %q", value)
+	}
+	v := value[i+1:]
+	*p = append(*p, v1alpha.Parameter{Name: value[:i], Value: &v})
+	return nil
+}
+
+var submitParameters parameters
+
+var submitCommand = &command{
+	usage: "submit [-p name=value]... FILE",
+	short: "Create a workflow from a YAML or JSON file, - for stdin",
+	flags: func(fs *flag.FlagSet, o *options) {
+		outputFlag(fs, o)
+		fs.Var(&submitParameters, "p", "set the value of a workflow parameter, may be repeated")
+	},
+	run: func(o *options, args []string) error {
+		if err := exactArgs(args, "FILE"); err != nil {
+			return err
+		}
+		wf, err := readWorkflow(args[0])
+		if err != nil {
+			return err
+		}
+		setParameters(wf, submitParameters)
+		return create(o, wf)
+	},
+}
+
+// readWorkflow reads a v1alpha or v1beta1 Workflow from file and returns it as
+// v1alpha, the version the operator works with.
+func readWorkflow(file string) (*v1alpha.Workflow, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data, err = yaml.YAMLToJSON(data); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	var meta metav1.TypeMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	if meta.Kind != "Workflow" {
+		return nil, fmt.Errorf("%s: expected a Workflow, got kind %q", file, meta.Kind)
+	}
+	switch meta.APIVersion {
+	case v1alpha.SchemeGroupVersion.String():
+		wf := &v1alpha.Workflow{}
+		if err := json.Unmarshal(data, wf); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		return wf, nil
+	case v1beta1.SchemeGroupVersion.String():
+		wf := &v1beta1.Workflow{}
+		if err := json.Unmarshal(data, wf); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		return v1beta1.ConvertToV1alpha(wf), nil
+	}
+	return nil, fmt.Errorf("%s: unknown apiVersion %q", file, meta.APIVersion)
+}
+
+// setParameters overrides the values of the workflow parameters of wf, adding
+// the ones it does not declare.
+func setParameters(wf *v1alpha.Workflow, params []v1alpha.Parameter) {
+	for _, param := range params {
+		found := false
+		for i := range wf.Spec.Arguments.Parameters {
+			if wf.Spec.Arguments.Parameters[i].Name == param.Name {
+				wf.Spec.Arguments.Parameters[i].Value = param.Value
+				found = true
+			}
+		}
+		if !found {
+			wf.Spec.Arguments.Parameters = append(wf.Spec.Arguments.Parameters, param)
+		}
+	}
+}
+
+// create creates wf in the namespace of o unless it names its own, and
+// prints the result.
+func create(o *options, wf *v1alpha.Workflow) error {
+	if wf.Namespace == "" {
+		wf.Namespace = o.namespace
+	}
+	o.namespace = wf.Namespace
+	client, err := o.workflows()
+	if err != nil {
+		return err
+	}
+	created, err := client.Create(wf)
+	if err != nil {
+		return err
+	}
+	if o.output != outputTable {
+		return o.print(created)
+	}
+	fmt.Fprintf(o.out, "workflow %s/%s created\n", created.Namespace, created.Name)
+	return nil
+}
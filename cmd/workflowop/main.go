@@ -2,26 +2,59 @@ package main
 
 import (
 	"context"
+	"os"
 	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/kube"
+	"github.com/Ziyang2go/workflowop/pkg/logsink"
+	"github.com/Ziyang2go/workflowop/pkg/mongo"
+	"github.com/Ziyang2go/workflowop/pkg/quota"
 	stub "github.com/Ziyang2go/workflowop/pkg/stub"
+	template "github.com/Ziyang2go/workflowop/pkg/templates"
+	"github.com/Ziyang2go/workflowop/pkg/webhook"
 	sdk "github.com/operator-framework/operator-sdk/pkg/sdk"
 	k8sutil "github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
 	sdkVersion "github.com/operator-framework/operator-sdk/version"
 
 	"github.com/Ziyang2go/workflowop/pkg/workflow"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 )
 
+const (
+	defaultJobTypesConfigMap = "workflowop-job-types"
+	defaultQuotaConfigMap    = "workflowop-quotas"
+	defaultWebhookPort       = 8443
+)
+
 func printVersion() {
 	logrus.Infof("Go Version: %s", runtime.Version())
 	logrus.Infof("Go OS/Arch: %s/%s", runtime.GOOS, runtime.GOARCH)
 	logrus.Infof("operator-sdk Version: %v", sdkVersion.Version)
 }
 
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		logrus.Fatalf("invalid %s %q: %v", key, value, err)
+	}
+	return n
+}
+
 func main() {
 	printVersion()
 
@@ -36,7 +69,75 @@ func main() {
 	resyncPeriod := time.Duration(20) * time.Second
 	logrus.Infof("Watching %s, %s, %s, %d", resource, kind, namespace, resyncPeriod)
 	sdk.Watch(resource, kind, namespace, resyncPeriod)
-	sdk.Watch("batch/v1", "Job", namespace, resyncPeriod)
-	sdk.Handle(stub.NewHandler(operator.NewWorkflowOp(kube.NewKube())))
+	sdk.Watch(resource, "CronWorkflow", namespace, resyncPeriod)
+	sdk.Watch("v1", "ConfigMap", namespace, resyncPeriod)
+
+	provider := kube.NewKube()
+	jobTypesConfigMap := getEnv("JOB_TYPES_CONFIGMAP", defaultJobTypesConfigMap)
+	registry := template.NewRegistry()
+	cm, err := provider.GetKubeClient().CoreV1().ConfigMaps(namespace).Get(jobTypesConfigMap, metav1.GetOptions{})
+	if err != nil {
+		logrus.Warnf("failed to get job types ConfigMap %s: %v", jobTypesConfigMap, err)
+	} else if err := registry.Load(cm); err != nil {
+		logrus.Warnf("failed to load job types from %s: %v", jobTypesConfigMap, err)
+	}
+	quotaConfigMap := getEnv("QUOTA_CONFIGMAP", defaultQuotaConfigMap)
+	scheduler := quota.NewScheduler(operator.DefaultQuotaStaleAfter)
+	cm, err = provider.GetKubeClient().CoreV1().ConfigMaps(namespace).Get(quotaConfigMap, metav1.GetOptions{})
+	if err != nil {
+		logrus.Warnf("failed to get quota ConfigMap %s: %v", quotaConfigMap, err)
+	} else if err := scheduler.Load(cm); err != nil {
+		logrus.Warnf("failed to load quota policy from %s: %v", quotaConfigMap, err)
+	}
+	if service := os.Getenv("WEBHOOK_SERVICE"); service != "" {
+		err := webhook.Start(provider.GetKubeClient(), webhook.Config{
+			Namespace:         namespace,
+			ServiceName:       service,
+			Port:              getEnvInt("WEBHOOK_PORT", defaultWebhookPort),
+			ConfigurationName: getEnv("WEBHOOK_CONFIGURATION", service+"-"+namespace),
+		})
+		if err != nil {
+			logrus.Fatalf("failed to start webhook: %v", err)
+		}
+	}
+	// Jobs are watched by the job cache rather than by the SDK, which would
+	// open a second watch of the same Jobs.
+	jobs := operator.NewJobCache(provider.GetKubeClient(), namespace, resyncPeriod)
+	opts := []operator.Option{
+		operator.WithRegistry(registry, jobTypesConfigMap),
+		operator.WithQuota(scheduler, quotaConfigMap),
+		operator.WithJobCache(jobs),
+	}
+	opts = append(opts, operator.WithLogTailBytes(getEnvInt("LOG_TAIL_BYTES", operator.DefaultLogTailBytes)))
+	opts = append(opts, operator.WithRetention(operator.Retention{
+		Succeeded: time.Duration(getEnvInt("TTL_SECONDS_AFTER_SUCCESS", 0)) * time.Second,
+		Failed:    time.Duration(getEnvInt("TTL_SECONDS_AFTER_FAILURE", 0)) * time.Second,
+	}))
+	sink, err := logsink.FromEnv()
+	if err != nil {
+		logrus.Fatalf("failed to configure log sink: %v", err)
+	}
+	if sink != nil {
+		opts = append(opts, operator.WithLogSink(sink))
+	}
+	if host := os.Getenv("MONGO_HOST"); host != "" {
+		history, err := mongo.New(host, getEnv("MONGO_PORT", "27017"), getEnv("MONGO_DB", "workflowop"), getEnv("MONGO_COLLECTION", "jobs"))
+		if err != nil {
+			logrus.Fatalf("failed to connect to job history store: %v", err)
+		}
+		defer history.Close()
+		opts = append(opts, operator.WithHistory(history))
+	}
+	op := operator.NewWorkflowOp(provider, opts...)
+	if err := op.LabelJobs(); err != nil {
+		logrus.Errorf("failed to label jobs created before the upgrade: %v", err)
+	}
+	jobs.OnChange(op.HandleJob)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	if !jobs.Run(stopCh) {
+		logrus.Fatalf("failed to sync job cache")
+	}
+	sdk.Handle(stub.NewHandler(op))
 	sdk.Run(context.TODO())
 }
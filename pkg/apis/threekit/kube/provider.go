@@ -1,6 +1,9 @@
 package kube
 
 import (
+	"fmt"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
 	"github.com/operator-framework/operator-sdk/pkg/k8sclient"
 	"github.com/operator-framework/operator-sdk/pkg/sdk"
 	"github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
@@ -14,10 +17,13 @@ import (
 type Provider interface {
 	Create(object runtime.Object) error
 	Update(object runtime.Object) error
+	UpdateStatus(object runtime.Object) error
 	Get(object runtime.Object) error
 	Delete(object runtime.Object) error
 	GetKubeClient() kubernetes.Interface
 	ListJobs() (*batchv1.JobList, error)
+	ListWorkflows(namespace, labelSelector string) (*v1alpha.WorkflowList, error)
+	DeleteJob(namespace, name string) error
 }
 
 type Kube struct {
@@ -35,6 +41,30 @@ func (k *Kube) Update(object runtime.Object) error {
 	return sdk.Update(object)
 }
 
+// UpdateStatus writes the status of object through the status subresource
+// and updates object with the result from the server.
+func (k *Kube) UpdateStatus(object runtime.Object) error {
+	_, namespace, err := k8sutil.GetNameAndNamespace(object)
+	if err != nil {
+		return err
+	}
+	gvk := object.GetObjectKind().GroupVersionKind()
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	resourceClient, _, err := k8sclient.GetResourceClient(apiVersion, kind, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get resource client: %v", err)
+	}
+	unstructObj, err := k8sutil.UnstructuredFromRuntimeObject(object)
+	if err != nil {
+		return err
+	}
+	unstructObj, err = resourceClient.UpdateStatus(unstructObj)
+	if err != nil {
+		return err
+	}
+	return k8sutil.UnstructuredIntoRuntimeObject(unstructObj, object)
+}
+
 func (k *Kube) Get(object runtime.Object) error {
 	return sdk.Get(object)
 }
@@ -61,3 +91,31 @@ func (k *Kube) ListJobs() (*batchv1.JobList, error) {
 	listErr := sdk.List(namespace, jl)
 	return jl, listErr
 }
+
+// ListWorkflows lists the workflows of namespace matching labelSelector.
+func (k *Kube) ListWorkflows(namespace, labelSelector string) (*v1alpha.WorkflowList, error) {
+	wl := &v1alpha.WorkflowList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Workflow",
+			APIVersion: "threekit.com/v1alpha",
+		},
+	}
+	err := sdk.List(namespace, wl, sdk.WithListOptions(&metav1.ListOptions{LabelSelector: labelSelector}))
+	return wl, err
+}
+
+// DeleteJob deletes a batch Job together with its pods.
+func (k *Kube) DeleteJob(namespace, name string) error {
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	propagation := metav1.DeletePropagationBackground
+	return sdk.Delete(job, sdk.WithDeleteOptions(&metav1.DeleteOptions{PropagationPolicy: &propagation}))
+}
@@ -0,0 +1,79 @@
+package v1alpha
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CronWorkflowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CronWorkflow `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CronWorkflow creates a Workflow on a cron schedule.
+type CronWorkflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              CronWorkflowSpec   `json:"spec"`
+	Status            CronWorkflowStatus `json:"status,omitempty"`
+}
+
+// ConcurrencyPolicy decides what happens when a CronWorkflow is due while a
+// workflow it created is still running.
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent runs the workflows side by side.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips the run until the running workflow finished.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent deletes the running workflow and starts a new one.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// CronWorkflowLabel is set on every Workflow created by a CronWorkflow to
+// the name of the CronWorkflow.
+const CronWorkflowLabel = "threekit.com/cron-workflow"
+
+type CronWorkflowSpec struct {
+	// Schedule is a five field cron schedule, e.g. "0 2 * * *".
+	Schedule string `json:"schedule"`
+	// Timezone is the IANA time zone Schedule is read in. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// ConcurrencyPolicy defaults to Allow.
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// StartingDeadlineSeconds skips a run that could not start within this
+	// many seconds of its scheduled time, e.g. while the operator was down.
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// Suspend stops new runs. Running workflows are not affected.
+	Suspend bool `json:"suspend,omitempty"`
+	// SuccessfulHistoryLimit is how many finished "ok" workflows are kept.
+	// Defaults to 3.
+	SuccessfulHistoryLimit *int32 `json:"successfulHistoryLimit,omitempty"`
+	// FailedHistoryLimit is how many "failed" or "cancelled" workflows are
+	// kept. Defaults to 1.
+	FailedHistoryLimit *int32 `json:"failedHistoryLimit,omitempty"`
+	// Workflow is the workflow created on every run.
+	Workflow CronWorkflowTemplate `json:"workflow"`
+}
+
+// CronWorkflowTemplate describes the workflows created by a CronWorkflow.
+type CronWorkflowTemplate struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Inputs      WorkflowInputs    `json:"inputs,omitempty"`
+	Spec        WorkflowSpec      `json:"spec,omitempty"`
+}
+
+type CronWorkflowStatus struct {
+	// Active references the workflows that did not finish yet.
+	Active []corev1.ObjectReference `json:"active,omitempty"`
+	// LastScheduleTime is the scheduled time of the last run started.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+}
@@ -0,0 +1,115 @@
+package v1alpha
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The SetDefaults_ functions are called by the generated SetObjectDefaults_
+// functions. The operator applies them to every workflow it handles and the
+// admission webhook to every workflow created, so a defaulted workflow has
+// non-nil labels and status maps. Job names are normalized by
+// NormalizeJobNames, at admission only.
+
+var (
+	// placeholderPattern matches the {{...}} substitutions of job names and
+	// data, which are left alone by NormalizeJobName.
+	placeholderPattern = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+	// dataJobRefPattern matches the job name of {{jobs.<name>.outputs.<field>}}.
+	dataJobRefPattern = regexp.MustCompile(`(\{\{\s*jobs\.)([^.{}\s]+)(\.)`)
+	// whenJobRefPattern matches the job name of jobs.<name>.status and
+	// jobs.<name>.outputs.<field> in when expressions.
+	whenJobRefPattern = regexp.MustCompile(`(^|[^A-Za-z0-9_.-])(jobs\.)([A-Za-z0-9_-]+)(\.)`)
+)
+
+func SetDefaults_Workflow(obj *Workflow) {
+	if obj.Labels == nil {
+		obj.Labels = make(map[string]string)
+	}
+	if obj.Status.Status == "" {
+		obj.Status.Status = WorkflowPending
+	}
+	if obj.Status.JobStatus == nil {
+		obj.Status.JobStatus = make(map[string]JobPhase)
+	}
+	if obj.Status.JobBatch == nil {
+		obj.Status.JobBatch = make(map[string]*BatchReference)
+	}
+	if obj.Status.FanOut == nil {
+		obj.Status.FanOut = make(map[string]FanOutStatus)
+	}
+}
+
+// SetDefaults_WorkflowInputs gives the workflow retry strategy to every job
+// that does not set its own.
+func SetDefaults_WorkflowInputs(obj *WorkflowInputs) {
+	if obj.RetryStrategy == nil {
+		return
+	}
+	for i := range obj.Jobs {
+		if obj.Jobs[i].RetryStrategy == nil {
+			obj.Jobs[i].RetryStrategy = obj.RetryStrategy.DeepCopy()
+		}
+	}
+}
+
+func SetDefaults_RetryStrategy(obj *RetryStrategy) {
+	if obj.Limit < 0 {
+		obj.Limit = 0
+	}
+	if obj.Backoff != nil && obj.Backoff.Factor < 1 {
+		obj.Backoff.Factor = 1
+	}
+}
+
+// NormalizeJobNames normalizes the name of every job of inputs and of the
+// jobs they refer to. It is not a defaulter: the operator handles a workflow
+// with the job names it was stored with, which its status refers to.
+func NormalizeJobNames(inputs *WorkflowInputs) {
+	for i := range inputs.Jobs {
+		normalizeJob(&inputs.Jobs[i])
+	}
+}
+
+func normalizeJob(obj *Job) {
+	obj.Name = NormalizeJobName(obj.Name)
+	for i, dep := range obj.DependsOn {
+		obj.DependsOn[i] = NormalizeJobName(dep)
+	}
+	if obj.WithParam != "" {
+		obj.WithParam = NormalizeJobName(obj.WithParam)
+	}
+	obj.Data = dataJobRefPattern.ReplaceAllStringFunc(obj.Data, func(ref string) string {
+		match := dataJobRefPattern.FindStringSubmatch(ref)
+		return match[1] + NormalizeJobName(match[2]) + match[3]
+	})
+	obj.When = whenJobRefPattern.ReplaceAllStringFunc(obj.When, func(ref string) string {
+		match := whenJobRefPattern.FindStringSubmatch(ref)
+		return match[1] + match[2] + NormalizeJobName(match[3]) + match[4]
+	})
+}
+
+// NormalizeJobName makes a job name usable in batch Job names: it trims
+// spaces, lower cases the name and replaces underscores and spaces by dashes.
+// Parameter references are kept as they are.
+func NormalizeJobName(name string) string {
+	name = strings.TrimSpace(name)
+	var normalized strings.Builder
+	last := 0
+	for _, loc := range placeholderPattern.FindAllStringIndex(name, -1) {
+		normalized.WriteString(normalizeJobNamePart(name[last:loc[0]]))
+		normalized.WriteString(name[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	normalized.WriteString(normalizeJobNamePart(name[last:]))
+	return normalized.String()
+}
+
+func normalizeJobNamePart(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == ' ' {
+			return '-'
+		}
+		return r
+	}, strings.ToLower(s))
+}
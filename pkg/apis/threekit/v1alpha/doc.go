@@ -1,3 +1,5 @@
 // +k8s:deepcopy-gen=package
+// +k8s:openapi-gen=true
+// +k8s:defaulter-gen=TypeMeta
 // +groupName=threekit.com
 package v1alpha
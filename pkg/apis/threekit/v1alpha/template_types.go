@@ -0,0 +1,49 @@
+package v1alpha
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type WorkflowTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []WorkflowTemplate `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WorkflowTemplate is a reusable, parameterized job graph. Workflows refer to
+// it with WorkflowSpec.WorkflowTemplateRef.
+type WorkflowTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              WorkflowTemplateSpec `json:"spec"`
+}
+
+type WorkflowTemplateSpec struct {
+	// Inputs are copied into every workflow created from the template.
+	Inputs WorkflowInputs `json:"inputs"`
+	// Arguments declare the parameters of the template and their defaults.
+	// Values given by the workflow take precedence.
+	Arguments Arguments `json:"arguments,omitempty"`
+}
+
+// WorkflowTemplateRef names the WorkflowTemplate, in the namespace of the
+// workflow, that a workflow is created from.
+type WorkflowTemplateRef struct {
+	Name string `json:"name"`
+}
+
+// WorkflowTemplateStatus records the template a workflow was created from.
+type WorkflowTemplateStatus struct {
+	Name            string `json:"name"`
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// WorkflowTemplateAnnotation is set on a workflow to the resourceVersion of
+// the template its inputs were copied from.
+const WorkflowTemplateAnnotation = "threekit.com/workflow-template-version"
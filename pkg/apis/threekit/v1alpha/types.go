@@ -1,7 +1,9 @@
 package v1alpha
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -12,37 +14,290 @@ type WorkflowList struct {
 	Items           []Workflow `json:"items"`
 }
 
+// OrganizationLabel is the Workflow label naming the organization that owns
+// the workflow. Job history and quotas are kept per organization; workflows
+// without it share the quota of the "" organization.
+const OrganizationLabel = "organization"
+
+// +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type Workflow struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Inputs            WorkflowInputs `json:"inputs"`
-	Spec              WorkflowSpec   `json:"spec"`
+	Inputs            WorkflowInputs `json:"inputs,omitempty"`
+	Spec              WorkflowSpec   `json:"spec,omitempty"`
 	Status            WorkflowStatus `json:"status,omitempty"`
 }
 
 type WorkflowSpec struct {
-	JobBatch map[string]*BatchReference `json:"jobBatch"`
+	// Suspend stops the operator from creating new jobs. Running jobs finish
+	// normally and scheduling resumes when Suspend is cleared.
+	Suspend bool `json:"suspend,omitempty"`
+	// Cancel deletes every running job and ends the workflow as "cancelled".
+	Cancel bool `json:"cancel,omitempty"`
+	// TTLSecondsAfterFinished is how long the workflow is kept once it
+	// finished. The operator-wide retention applies when unset.
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// Priority orders the admission of queued jobs when the job limits are
+	// reached: jobs of higher priority workflows are admitted first, and
+	// older workflows first within the same priority.
+	Priority int32 `json:"priority,omitempty"`
+	// PriorityClassName is the Kubernetes PriorityClass of the pods of the
+	// workflow's jobs.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Arguments are substituted into the jobs of the workflow.
+	Arguments Arguments `json:"arguments,omitempty"`
+	// WorkflowTemplateRef creates the workflow from a WorkflowTemplate. Its
+	// inputs must then be empty: they are copied from the template, and the
+	// template arguments merged with Arguments, when the workflow is
+	// admitted.
+	WorkflowTemplateRef *WorkflowTemplateRef `json:"workflowTemplateRef,omitempty"`
+	// Deprecated: JobBatch moved to WorkflowStatus. It is only read to
+	// migrate workflows started by older operators.
+	JobBatch map[string]*BatchReference `json:"jobBatch,omitempty"`
+}
+
+// Arguments of a Workflow.
+type Arguments struct {
+	// Parameters are referenced as {{workflow.parameters.<name>}} in the
+	// name, dependsOn and data of jobs, in the env values of job types, and
+	// as workflow.parameters.<name> in when expressions.
+	Parameters []Parameter `json:"parameters,omitempty"`
+}
+
+// Parameter is a named string argument. A parameter with neither a value
+// nor a default is required: the workflow is rejected without it.
+type Parameter struct {
+	Name    string  `json:"name"`
+	Value   *string `json:"value,omitempty"`
+	Default *string `json:"default,omitempty"`
+}
+
+// WorkflowPhase is the lifecycle phase of a Workflow.
+type WorkflowPhase string
+
+const (
+	WorkflowPending WorkflowPhase = "pending"
+	WorkflowWorking WorkflowPhase = "working"
+	WorkflowOK      WorkflowPhase = "ok"
+	WorkflowFailed  WorkflowPhase = "failed"
+	// WorkflowTerminating is set while the jobs of a cancelled workflow are
+	// being deleted.
+	WorkflowTerminating WorkflowPhase = "terminating"
+	WorkflowCancelled   WorkflowPhase = "cancelled"
+)
+
+// Finished reports whether the workflow reached a phase it never leaves.
+func (p WorkflowPhase) Finished() bool {
+	switch p {
+	case WorkflowOK, WorkflowFailed, WorkflowCancelled:
+		return true
+	}
+	return false
+}
+
+// JobPhase is the lifecycle phase of one job of a Workflow.
+type JobPhase string
+
+const (
+	JobPending JobPhase = "pending"
+	// JobQueued jobs are ready to run but wait for their organization's
+	// quota or the operator's capacity.
+	JobQueued    JobPhase = "queued"
+	JobWorking   JobPhase = "working"
+	JobRetrying  JobPhase = "retrying"
+	JobOK        JobPhase = "ok"
+	JobFailed    JobPhase = "failed"
+	JobSkipped   JobPhase = "skipped"
+	JobTimedOut  JobPhase = "timedOut"
+	JobCancelled JobPhase = "cancelled"
+)
+
+// Finished reports whether the job reached a phase it never leaves.
+func (p JobPhase) Finished() bool {
+	switch p {
+	case JobOK, JobFailed, JobSkipped, JobTimedOut, JobCancelled:
+		return true
+	}
+	return false
 }
 
 type WorkflowStatus struct {
-	Status    string            `json:"status"`
-	JobStatus map[string]string `json:"jobStatus"`
+	// Status is the phase of the workflow. An empty phase means pending.
+	Status    WorkflowPhase       `json:"status"`
+	JobStatus map[string]JobPhase `json:"jobStatus,omitempty"`
+	// JobBatch references the batch Job run for each job.
+	JobBatch map[string]*BatchReference `json:"jobBatch,omitempty"`
+	// FanOut aggregates the child jobs of every fan-out job. The phase of a
+	// fan-out job in JobStatus is derived from its children.
+	FanOut map[string]FanOutStatus `json:"fanOut,omitempty"`
+	// StartedAt is when the workflow was admitted and moved to "working".
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// FinishedAt is when the workflow reached "ok" or "failed".
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+	// ObservedGeneration is the workflow generation last handled by the
+	// operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// WorkflowTemplate is the template the workflow was created from.
+	WorkflowTemplate *WorkflowTemplateStatus `json:"workflowTemplate,omitempty"`
+	Conditions       []WorkflowCondition     `json:"conditions,omitempty"`
+}
+
+type WorkflowConditionType string
+
+const (
+	// WorkflowAdmitted is true once the workflow was validated and started.
+	WorkflowAdmitted WorkflowConditionType = "Admitted"
+	// WorkflowCompleted is true once every job finished.
+	WorkflowCompleted WorkflowConditionType = "Completed"
+	// WorkflowFailedCondition is true when the workflow failed. Its reason
+	// tells why.
+	WorkflowFailedCondition WorkflowConditionType = "Failed"
+	// WorkflowSuspended is true while spec.suspend holds back new jobs.
+	WorkflowSuspended WorkflowConditionType = "Suspended"
+)
+
+// Reasons set on workflow conditions.
+const (
+	ReasonAdmitted         = "Admitted"
+	ReasonInvalidJobGraph  = "InvalidJobGraph"
+	ReasonInvalidArguments = "InvalidArguments"
+	ReasonInvalidTemplate  = "InvalidTemplate"
+	ReasonUnknownPhase     = "UnknownPhase"
+	ReasonJobsSucceeded    = "JobsSucceeded"
+	ReasonJobFailed        = "JobFailed"
+	ReasonDeadlineExceeded = "DeadlineExceeded"
+	ReasonSuspended        = "Suspended"
+	ReasonResumed          = "Resumed"
+	ReasonCancelled        = "Cancelled"
+	// ReasonConditionFalse marks a job skipped because its when expression
+	// was false.
+	ReasonConditionFalse = "ConditionFalse"
+)
+
+type WorkflowCondition struct {
+	Type               WorkflowConditionType  `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime"`
 }
 
 type WorkflowInputs struct {
-	Jobs []Job `json:"jobs"`
+	Jobs []Job `json:"jobs,omitempty"`
+	// RetryStrategy applies to every job that does not set its own.
+	RetryStrategy *RetryStrategy `json:"retryStrategy,omitempty"`
+	// ActiveDeadlineSeconds bounds how long the workflow may run once
+	// started. Running jobs are deleted and the workflow fails when exceeded.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
 }
 
 type Job struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
 	Data string `json:"data"`
+	// DependsOn lists the names of jobs in the same workflow that must
+	// finish with status "ok" before this job is created.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// RetryStrategy overrides the workflow retry strategy for this job.
+	RetryStrategy *RetryStrategy `json:"retryStrategy,omitempty"`
+	// ActiveDeadlineSeconds bounds how long each attempt of the job may run.
+	// The job is deleted and marked "timedOut" when exceeded.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// WithItems fans the job out into one child job per item, named
+	// <name>-<index>. {{item}} in Data is replaced by the item, and
+	// {{item.field}} by a field of an object item.
+	WithItems []runtime.RawExtension `json:"withItems,omitempty"`
+	// WithParam fans the job out like WithItems, over the JSON array result
+	// of the named job. That job must be listed in DependsOn.
+	WithParam string `json:"withParam,omitempty"`
+	// Parallelism caps how many child jobs of a fan-out job run at once.
+	Parallelism *int32 `json:"parallelism,omitempty"`
+	// When is a condition evaluated once every job in DependsOn finished,
+	// whatever its phase. The job is "skipped" when it is false, which
+	// does not fail the workflow and lets dependent jobs run.
+	When string `json:"when,omitempty"`
+}
+
+// FanOut reports whether the job runs as child jobs over a list of items.
+func (j Job) FanOut() bool {
+	return len(j.WithItems) > 0 || j.WithParam != ""
+}
+
+// Failure reasons recorded for a job attempt and matched by
+// RetryStrategy.RetryOn.
+const (
+	FailureReasonError            = "Error"
+	FailureReasonOOMKilled        = "OOMKilled"
+	FailureReasonEvicted          = "Evicted"
+	FailureReasonDeadlineExceeded = "DeadlineExceeded"
+)
+
+// RetryStrategy describes how a failed job is retried.
+type RetryStrategy struct {
+	// Limit is the number of retries after the first attempt.
+	Limit int32 `json:"limit"`
+	// Backoff delays each retry. Retries start immediately when unset.
+	Backoff *Backoff `json:"backoff,omitempty"`
+	// RetryOn lists the failure reasons that are retried. Every failure is
+	// retried when empty.
+	RetryOn []string `json:"retryOn,omitempty"`
+}
+
+// Backoff is an exponential delay between job attempts: the n-th retry waits
+// Duration * Factor^(n-1), at most MaxDuration.
+type Backoff struct {
+	Duration    string `json:"duration"`
+	Factor      int32  `json:"factor,omitempty"`
+	MaxDuration string `json:"maxDuration,omitempty"`
 }
 
 type BatchReference struct {
 	Kind string `json:"kind"`
 	Name string `json:"name"`
 	Logs string `json:"logs"`
+	// Message explains why the job could not run, e.g. an unknown job type.
+	Message string `json:"message,omitempty"`
+	// Reason is ReasonConditionFalse for a job skipped by its when
+	// expression.
+	Reason string `json:"reason,omitempty"`
+	// LogURL locates the complete log archived by the operator's log sink;
+	// Logs then only holds its tail.
+	LogURL      string `json:"logURL,omitempty"`
+	LogSize     int64  `json:"logSize,omitempty"`
+	LogChecksum string `json:"logChecksum,omitempty"`
+	// Attempts records the outcome of every finished attempt of the job.
+	// Name is the batch Job of the current attempt.
+	Attempts []JobAttempt `json:"attempts,omitempty"`
+	// RetryAfter is when the next attempt of a "retrying" job is created.
+	RetryAfter *metav1.Time `json:"retryAfter,omitempty"`
+	// StartedAt is when the batch Job of the current attempt was created.
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// FinishedAt is when the job reached a finished phase.
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+	// Result is the termination message of the job's container once it
+	// succeeded.
+	Result string `json:"result,omitempty"`
+	// Outputs are the fields of Result when it is a JSON object. String
+	// values are kept as is, other values as JSON. Downstream jobs refer to
+	// them as {{jobs.<name>.outputs.<field>}} in their Data.
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// FanOutStatus counts the child jobs of a job with WithItems or WithParam.
+type FanOutStatus struct {
+	Total     int32 `json:"total"`
+	Succeeded int32 `json:"succeeded"`
+	Failed    int32 `json:"failed"`
+	Running   int32 `json:"running"`
+	// Message explains why the items could not be expanded.
+	Message string `json:"message,omitempty"`
+}
+
+type JobAttempt struct {
+	Name       string      `json:"name"`
+	Status     JobPhase    `json:"status"`
+	Reason     string      `json:"reason,omitempty"`
+	FinishedAt metav1.Time `json:"finishedAt"`
 }
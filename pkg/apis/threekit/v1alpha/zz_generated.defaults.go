@@ -0,0 +1,84 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by defaulter-gen. DO NOT EDIT.
+
+package v1alpha
+
+import (
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// RegisterDefaults adds defaulters functions to the given scheme.
+// Public to allow building arbitrary schemes.
+// All generated defaulters are covering - they call all nested defaulters.
+func RegisterDefaults(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&CronWorkflow{}, func(obj interface{}) { SetObjectDefaults_CronWorkflow(obj.(*CronWorkflow)) })
+	scheme.AddTypeDefaultingFunc(&CronWorkflowList{}, func(obj interface{}) { SetObjectDefaults_CronWorkflowList(obj.(*CronWorkflowList)) })
+	scheme.AddTypeDefaultingFunc(&Workflow{}, func(obj interface{}) { SetObjectDefaults_Workflow(obj.(*Workflow)) })
+	scheme.AddTypeDefaultingFunc(&WorkflowList{}, func(obj interface{}) { SetObjectDefaults_WorkflowList(obj.(*WorkflowList)) })
+	scheme.AddTypeDefaultingFunc(&WorkflowTemplate{}, func(obj interface{}) { SetObjectDefaults_WorkflowTemplate(obj.(*WorkflowTemplate)) })
+	scheme.AddTypeDefaultingFunc(&WorkflowTemplateList{}, func(obj interface{}) { SetObjectDefaults_WorkflowTemplateList(obj.(*WorkflowTemplateList)) })
+	return nil
+}
+
+func SetObjectDefaults_CronWorkflow(in *CronWorkflow) {
+	SetDefaults_WorkflowInputs(&in.Spec.Workflow.Inputs)
+	for i := range in.Spec.Workflow.Inputs.Jobs {
+		a := &in.Spec.Workflow.Inputs.Jobs[i]
+		if a.RetryStrategy != nil {
+			SetDefaults_RetryStrategy(a.RetryStrategy)
+		}
+	}
+	if in.Spec.Workflow.Inputs.RetryStrategy != nil {
+		SetDefaults_RetryStrategy(in.Spec.Workflow.Inputs.RetryStrategy)
+	}
+}
+
+func SetObjectDefaults_CronWorkflowList(in *CronWorkflowList) {
+	for i := range in.Items {
+		a := &in.Items[i]
+		SetObjectDefaults_CronWorkflow(a)
+	}
+}
+
+func SetObjectDefaults_Workflow(in *Workflow) {
+	SetDefaults_Workflow(in)
+	SetDefaults_WorkflowInputs(&in.Inputs)
+	for i := range in.Inputs.Jobs {
+		a := &in.Inputs.Jobs[i]
+		if a.RetryStrategy != nil {
+			SetDefaults_RetryStrategy(a.RetryStrategy)
+		}
+	}
+	if in.Inputs.RetryStrategy != nil {
+		SetDefaults_RetryStrategy(in.Inputs.RetryStrategy)
+	}
+}
+
+func SetObjectDefaults_WorkflowList(in *WorkflowList) {
+	for i := range in.Items {
+		a := &in.Items[i]
+		SetObjectDefaults_Workflow(a)
+	}
+}
+
+func SetObjectDefaults_WorkflowTemplate(in *WorkflowTemplate) {
+	SetDefaults_WorkflowInputs(&in.Spec.Inputs)
+	for i := range in.Spec.Inputs.Jobs {
+		a := &in.Spec.Inputs.Jobs[i]
+		if a.RetryStrategy != nil {
+			SetDefaults_RetryStrategy(a.RetryStrategy)
+		}
+	}
+	if in.Spec.Inputs.RetryStrategy != nil {
+		SetDefaults_RetryStrategy(in.Spec.Inputs.RetryStrategy)
+	}
+}
+
+func SetObjectDefaults_WorkflowTemplateList(in *WorkflowTemplateList) {
+	for i := range in.Items {
+		a := &in.Items[i]
+		SetObjectDefaults_WorkflowTemplate(a)
+	}
+}
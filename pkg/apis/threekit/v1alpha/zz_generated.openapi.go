@@ -0,0 +1,1207 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by openapi-gen. DO NOT EDIT.
+
+// This file was autogenerated by openapi-gen. Do not edit it manually!
+
+package v1alpha
+
+import (
+	spec "github.com/go-openapi/spec"
+	common "k8s.io/kube-openapi/pkg/common"
+)
+
+func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+	return map[string]common.OpenAPIDefinition{
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Arguments":              schema_pkg_apis_threekit_v1alpha_Arguments(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Backoff":                schema_pkg_apis_threekit_v1alpha_Backoff(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.BatchReference":         schema_pkg_apis_threekit_v1alpha_BatchReference(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflow":           schema_pkg_apis_threekit_v1alpha_CronWorkflow(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflowList":       schema_pkg_apis_threekit_v1alpha_CronWorkflowList(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflowSpec":       schema_pkg_apis_threekit_v1alpha_CronWorkflowSpec(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflowStatus":     schema_pkg_apis_threekit_v1alpha_CronWorkflowStatus(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflowTemplate":   schema_pkg_apis_threekit_v1alpha_CronWorkflowTemplate(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.FanOutStatus":           schema_pkg_apis_threekit_v1alpha_FanOutStatus(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Job":                    schema_pkg_apis_threekit_v1alpha_Job(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.JobAttempt":             schema_pkg_apis_threekit_v1alpha_JobAttempt(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Parameter":              schema_pkg_apis_threekit_v1alpha_Parameter(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.RetryStrategy":          schema_pkg_apis_threekit_v1alpha_RetryStrategy(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Workflow":               schema_pkg_apis_threekit_v1alpha_Workflow(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowCondition":      schema_pkg_apis_threekit_v1alpha_WorkflowCondition(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowInputs":         schema_pkg_apis_threekit_v1alpha_WorkflowInputs(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowList":           schema_pkg_apis_threekit_v1alpha_WorkflowList(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowSpec":           schema_pkg_apis_threekit_v1alpha_WorkflowSpec(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowStatus":         schema_pkg_apis_threekit_v1alpha_WorkflowStatus(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplate":       schema_pkg_apis_threekit_v1alpha_WorkflowTemplate(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateList":   schema_pkg_apis_threekit_v1alpha_WorkflowTemplateList(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateRef":    schema_pkg_apis_threekit_v1alpha_WorkflowTemplateRef(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateSpec":   schema_pkg_apis_threekit_v1alpha_WorkflowTemplateSpec(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateStatus": schema_pkg_apis_threekit_v1alpha_WorkflowTemplateStatus(ref),
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_Arguments(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Arguments of a Workflow.",
+				Properties: map[string]spec.Schema{
+					"parameters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Parameters are referenced as {{workflow.parameters.<name>}} in the name, dependsOn and data of jobs, in the env values of job types, and as workflow.parameters.<name> in when expressions.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Parameter"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Parameter"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_Backoff(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Backoff is an exponential delay between job attempts: the n-th retry waits Duration * Factor^(n-1), at most MaxDuration.",
+				Properties: map[string]spec.Schema{
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"factor": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"maxDuration": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"duration"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_BatchReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"logs": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message explains why the job could not run, e.g. an unknown job type.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is ReasonConditionFalse for a job skipped by its when expression.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"logURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LogURL locates the complete log archived by the operator's log sink; Logs then only holds its tail.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"logSize": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"logChecksum": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"attempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attempts records the outcome of every finished attempt of the job. Name is the batch Job of the current attempt.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.JobAttempt"),
+									},
+								},
+							},
+						},
+					},
+					"retryAfter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryAfter is when the next attempt of a \"retrying\" job is created.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"startedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StartedAt is when the batch Job of the current attempt was created.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"finishedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FinishedAt is when the job reached a finished phase.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"result": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Result is the termination message of the job's container once it succeeded.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"outputs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Outputs are the fields of Result when it is a JSON object. String values are kept as is, other values as JSON. Downstream jobs refer to them as {{jobs.<name>.outputs.<field>}} in their Data.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"kind", "name", "logs"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.JobAttempt", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_CronWorkflow(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CronWorkflow creates a Workflow on a cron schedule.",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflowSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflowStatus"),
+						},
+					},
+				},
+				Required: []string{"metadata", "spec"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflowSpec", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflowStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_CronWorkflowList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflow"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflow", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_CronWorkflowSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"schedule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Schedule is a five field cron schedule, e.g. \"0 2 * * *\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timezone": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timezone is the IANA time zone Schedule is read in. Defaults to UTC.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"concurrencyPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConcurrencyPolicy defaults to Allow.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"startingDeadlineSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StartingDeadlineSeconds skips a run that could not start within this many seconds of its scheduled time, e.g. while the operator was down.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"suspend": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Suspend stops new runs. Running workflows are not affected.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"successfulHistoryLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SuccessfulHistoryLimit is how many finished \"ok\" workflows are kept. Defaults to 3.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"failedHistoryLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailedHistoryLimit is how many \"failed\" or \"cancelled\" workflows are kept. Defaults to 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"workflow": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Workflow is the workflow created on every run.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflowTemplate"),
+						},
+					},
+				},
+				Required: []string{"schedule", "workflow"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.CronWorkflowTemplate"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_CronWorkflowStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"active": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Active references the workflows that did not finish yet.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.ObjectReference"),
+									},
+								},
+							},
+						},
+					},
+					"lastScheduleTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastScheduleTime is the scheduled time of the last run started.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_CronWorkflowTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CronWorkflowTemplate describes the workflows created by a CronWorkflow.",
+				Properties: map[string]spec.Schema{
+					"labels": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"inputs": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowInputs"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowInputs", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowSpec"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_FanOutStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FanOutStatus counts the child jobs of a job with WithItems or WithParam.",
+				Properties: map[string]spec.Schema{
+					"total": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"succeeded": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"failed": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"running": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message explains why the items could not be expanded.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"total", "succeeded", "failed", "running"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_Job(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"data": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"dependsOn": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DependsOn lists the names of jobs in the same workflow that must finish with status \"ok\" before this job is created.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"retryStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryStrategy overrides the workflow retry strategy for this job.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.RetryStrategy"),
+						},
+					},
+					"activeDeadlineSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ActiveDeadlineSeconds bounds how long each attempt of the job may run. The job is deleted and marked \"timedOut\" when exceeded.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"withItems": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WithItems fans the job out into one child job per item, named <name>-<index>. {{item}} in Data is replaced by the item, and {{item.field}} by a field of an object item.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/runtime.RawExtension"),
+									},
+								},
+							},
+						},
+					},
+					"withParam": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WithParam fans the job out like WithItems, over the JSON array result of the named job. That job must be listed in DependsOn.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"parallelism": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Parallelism caps how many child jobs of a fan-out job run at once.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"when": {
+						SchemaProps: spec.SchemaProps{
+							Description: "When is a condition evaluated once every job in DependsOn finished, whatever its phase. The job is \"skipped\" when it is false, which does not fail the workflow and lets dependent jobs run.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "type", "data"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.RetryStrategy", "k8s.io/apimachinery/pkg/runtime.RawExtension"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_JobAttempt(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"finishedAt": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"name", "status", "finishedAt"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_Parameter(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Parameter is a named string argument. A parameter with neither a value nor a default is required: the workflow is rejected without it.",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"default": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_RetryStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RetryStrategy describes how a failed job is retried.",
+				Properties: map[string]spec.Schema{
+					"limit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Limit is the number of retries after the first attempt.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"backoff": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backoff delays each retry. Retries start immediately when unset.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Backoff"),
+						},
+					},
+					"retryOn": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryOn lists the failure reasons that are retried. Every failure is retried when empty.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"limit"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Backoff"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_Workflow(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"inputs": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowInputs"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowStatus"),
+						},
+					},
+				},
+				Required: []string{"metadata"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowInputs", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowSpec", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_WorkflowCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"type", "status", "lastTransitionTime"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_WorkflowInputs(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"jobs": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Job"),
+									},
+								},
+							},
+						},
+					},
+					"retryStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryStrategy applies to every job that does not set its own.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.RetryStrategy"),
+						},
+					},
+					"activeDeadlineSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ActiveDeadlineSeconds bounds how long the workflow may run once started. Running jobs are deleted and the workflow fails when exceeded.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Job", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.RetryStrategy"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_WorkflowList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Workflow"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Workflow", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_WorkflowSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"suspend": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Suspend stops the operator from creating new jobs. Running jobs finish normally and scheduling resumes when Suspend is cleared.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"cancel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cancel deletes every running job and ends the workflow as \"cancelled\".",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"ttlSecondsAfterFinished": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TTLSecondsAfterFinished is how long the workflow is kept once it finished. The operator-wide retention applies when unset.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"priority": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Priority orders the admission of queued jobs when the job limits are reached: jobs of higher priority workflows are admitted first, and older workflows first within the same priority.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"priorityClassName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PriorityClassName is the Kubernetes PriorityClass of the pods of the workflow's jobs.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"arguments": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Arguments are substituted into the jobs of the workflow.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Arguments"),
+						},
+					},
+					"workflowTemplateRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkflowTemplateRef creates the workflow from a WorkflowTemplate. Its inputs must then be empty: they are copied from the template, and the template arguments merged with Arguments, when the workflow is admitted.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateRef"),
+						},
+					},
+					"jobBatch": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deprecated: JobBatch moved to WorkflowStatus. It is only read to migrate workflows started by older operators.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.BatchReference"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Arguments", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.BatchReference", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateRef"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_WorkflowStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status is the phase of the workflow. An empty phase means pending.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"jobStatus": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"jobBatch": {
+						SchemaProps: spec.SchemaProps{
+							Description: "JobBatch references the batch Job run for each job.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.BatchReference"),
+									},
+								},
+							},
+						},
+					},
+					"fanOut": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FanOut aggregates the child jobs of every fan-out job. The phase of a fan-out job in JobStatus is derived from its children.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.FanOutStatus"),
+									},
+								},
+							},
+						},
+					},
+					"startedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StartedAt is when the workflow was admitted and moved to \"working\".",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"finishedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FinishedAt is when the workflow reached \"ok\" or \"failed\".",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the workflow generation last handled by the operator.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"workflowTemplate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkflowTemplate is the template the workflow was created from.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateStatus"),
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowCondition"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"status"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.BatchReference", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.FanOutStatus", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowCondition", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_WorkflowTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WorkflowTemplate is a reusable, parameterized job graph. Workflows refer to it with WorkflowSpec.WorkflowTemplateRef.",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateSpec"),
+						},
+					},
+				},
+				Required: []string{"metadata", "spec"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_WorkflowTemplateList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplate"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplate", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_WorkflowTemplateRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WorkflowTemplateRef names the WorkflowTemplate, in the namespace of the workflow, that a workflow is created from.",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_WorkflowTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"inputs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Inputs are copied into every workflow created from the template.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowInputs"),
+						},
+					},
+					"arguments": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Arguments declare the parameters of the template and their defaults. Values given by the workflow take precedence.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Arguments"),
+						},
+					},
+				},
+				Required: []string{"inputs"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Arguments", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowInputs"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1alpha_WorkflowTemplateStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WorkflowTemplateStatus records the template a workflow was created from.",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"resourceVersion": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"name", "resourceVersion"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
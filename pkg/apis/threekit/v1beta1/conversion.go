@@ -0,0 +1,60 @@
+package v1beta1
+
+import (
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+)
+
+// ConvertFromV1alpha returns in as a v1beta1 Workflow. The batch references
+// that older operators kept in spec.jobBatch move to status.jobBatch unless
+// the status already has its own.
+func ConvertFromV1alpha(in *v1alpha.Workflow) *Workflow {
+	in = in.DeepCopy()
+	out := &Workflow{
+		ObjectMeta: in.ObjectMeta,
+		Spec: WorkflowSpec{
+			Jobs:                    in.Inputs.Jobs,
+			RetryStrategy:           in.Inputs.RetryStrategy,
+			ActiveDeadlineSeconds:   in.Inputs.ActiveDeadlineSeconds,
+			Suspend:                 in.Spec.Suspend,
+			Cancel:                  in.Spec.Cancel,
+			TTLSecondsAfterFinished: in.Spec.TTLSecondsAfterFinished,
+			Priority:                in.Spec.Priority,
+			PriorityClassName:       in.Spec.PriorityClassName,
+			Arguments:               in.Spec.Arguments,
+			WorkflowTemplateRef:     in.Spec.WorkflowTemplateRef,
+		},
+		Status: in.Status,
+	}
+	if len(out.Status.JobBatch) == 0 && len(in.Spec.JobBatch) > 0 {
+		out.Status.JobBatch = in.Spec.JobBatch
+	}
+	out.APIVersion = SchemeGroupVersion.String()
+	out.Kind = "Workflow"
+	return out
+}
+
+// ConvertToV1alpha returns in as a v1alpha Workflow.
+func ConvertToV1alpha(in *Workflow) *v1alpha.Workflow {
+	in = in.DeepCopy()
+	out := &v1alpha.Workflow{
+		ObjectMeta: in.ObjectMeta,
+		Inputs: v1alpha.WorkflowInputs{
+			Jobs:                  in.Spec.Jobs,
+			RetryStrategy:         in.Spec.RetryStrategy,
+			ActiveDeadlineSeconds: in.Spec.ActiveDeadlineSeconds,
+		},
+		Spec: v1alpha.WorkflowSpec{
+			Suspend:                 in.Spec.Suspend,
+			Cancel:                  in.Spec.Cancel,
+			TTLSecondsAfterFinished: in.Spec.TTLSecondsAfterFinished,
+			Priority:                in.Spec.Priority,
+			PriorityClassName:       in.Spec.PriorityClassName,
+			Arguments:               in.Spec.Arguments,
+			WorkflowTemplateRef:     in.Spec.WorkflowTemplateRef,
+		},
+		Status: in.Status,
+	}
+	out.APIVersion = v1alpha.SchemeGroupVersion.String()
+	out.Kind = "Workflow"
+	return out
+}
@@ -0,0 +1,125 @@
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// v1alphaWorkflow returns a v1alpha Workflow with every field of its spec and
+// inputs set, apart from the deprecated spec.jobBatch.
+func v1alphaWorkflow() *v1alpha.Workflow {
+	value, ttl, deadline := "s1", int32(60), int64(600)
+	started := metav1.Date(2018, 9, 3, 12, 0, 0, 0, time.UTC)
+	strategy := &v1alpha.RetryStrategy{Limit: 2, Backoff: &v1alpha.Backoff{Duration: "30s", Factor: 2, MaxDuration: "5m"}}
+	return &v1alpha.Workflow{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "threekit.com/v1alpha", Kind: "Workflow"},
+		ObjectMeta: metav1.ObjectMeta{Name: "wf", Namespace: "ns", Labels: map[string]string{v1alpha.OrganizationLabel: "acme"}},
+		Spec: v1alpha.WorkflowSpec{
+			Suspend:                 true,
+			Cancel:                  true,
+			TTLSecondsAfterFinished: &ttl,
+			Priority:                5,
+			PriorityClassName:       "high",
+			Arguments:               v1alpha.Arguments{Parameters: []v1alpha.Parameter{{Name: "scene", Value: &value}}},
+			WorkflowTemplateRef:     &v1alpha.WorkflowTemplateRef{Name: "render"},
+		},
+		Inputs: v1alpha.WorkflowInputs{
+			Jobs: []v1alpha.Job{
+				{Name: "import", Type: "import", Data: `{"scene":"{{workflow.parameters.scene}}"}`},
+				{Name: "render", Type: "render", DependsOn: []string{"import"}, RetryStrategy: strategy},
+			},
+			RetryStrategy:         strategy,
+			ActiveDeadlineSeconds: &deadline,
+		},
+		Status: v1alpha.WorkflowStatus{
+			Status:             v1alpha.WorkflowWorking,
+			JobStatus:          map[string]v1alpha.JobPhase{"import": v1alpha.JobOK, "render": v1alpha.JobWorking},
+			FanOut:             map[string]v1alpha.FanOutStatus{"render": {Total: 2, Running: 1}},
+			StartedAt:          &started,
+			ObservedGeneration: 3,
+			WorkflowTemplate:   &v1alpha.WorkflowTemplateStatus{Name: "render", ResourceVersion: "42"},
+			Conditions: []v1alpha.WorkflowCondition{
+				{Type: v1alpha.WorkflowAdmitted, Status: corev1.ConditionTrue, Reason: "Admitted"},
+			},
+		},
+	}
+}
+
+func batches(prefix string) map[string]*v1alpha.BatchReference {
+	return map[string]*v1alpha.BatchReference{
+		"import": {Kind: "Job", Name: prefix + "-import"},
+		"render": {Kind: "Job", Name: prefix + "-render"},
+	}
+}
+
+// unset returns the names of the fields of the struct v that are not set.
+func unset(v interface{}) []string {
+	var names []string
+	value := reflect.ValueOf(v)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if reflect.DeepEqual(field.Interface(), reflect.Zero(field.Type()).Interface()) {
+			names = append(names, value.Type().Field(i).Name)
+		}
+	}
+	return names
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	// The round trip only shows that every field is converted when every
+	// field is set.
+	wf := v1alphaWorkflow()
+	if names := unset(wf.Spec); len(names) != 1 || names[0] != "JobBatch" {
+		t.Fatalf("spec fields %v are not set in the test workflow", names)
+	}
+	if names := unset(wf.Inputs); len(names) != 0 {
+		t.Fatalf("inputs fields %v are not set in the test workflow", names)
+	}
+
+	tests := []struct {
+		name        string
+		specBatch   map[string]*v1alpha.BatchReference
+		statusBatch map[string]*v1alpha.BatchReference
+		want        map[string]*v1alpha.BatchReference
+	}{
+		{name: "status", statusBatch: batches("status"), want: batches("status")},
+		{name: "spec jobBatch", specBatch: batches("spec"), want: batches("spec")},
+		{name: "both", specBatch: batches("spec"), statusBatch: batches("status"), want: batches("status")},
+		{name: "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := v1alphaWorkflow()
+			in.Spec.JobBatch = tt.specBatch
+			in.Status.JobBatch = tt.statusBatch
+			orig := in.DeepCopy()
+
+			beta := ConvertFromV1alpha(in)
+			if !reflect.DeepEqual(in, orig) {
+				t.Errorf("ConvertFromV1alpha() changed its input to %+v", in)
+			}
+			if beta.APIVersion != "threekit.com/v1beta1" || beta.Kind != "Workflow" {
+				t.Errorf("ConvertFromV1alpha() is a %s %s, want a threekit.com/v1beta1 Workflow", beta.APIVersion, beta.Kind)
+			}
+			if names := unset(beta.Spec); len(names) != 0 {
+				t.Errorf("spec fields %v are not converted", names)
+			}
+			if !reflect.DeepEqual(beta.Status.JobBatch, tt.want) {
+				t.Errorf("status.jobBatch = %v, want %v", beta.Status.JobBatch, tt.want)
+			}
+
+			out := ConvertToV1alpha(beta)
+			want := orig.DeepCopy()
+			want.Spec.JobBatch = nil
+			want.Status.JobBatch = tt.want
+			if !reflect.DeepEqual(out, want) {
+				t.Errorf("round trip = %+v, want %+v", out, want)
+			}
+		})
+	}
+}
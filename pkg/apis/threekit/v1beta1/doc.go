@@ -0,0 +1,7 @@
+// Package v1beta1 is the threekit.com/v1beta1 API. A Workflow keeps what the
+// user asked for under spec and everything the operator records under
+// status. The nested types are shared with v1alpha until they diverge.
+// +k8s:deepcopy-gen=package
+// +k8s:openapi-gen=true
+// +groupName=threekit.com
+package v1beta1
@@ -0,0 +1,54 @@
+package v1beta1
+
+import (
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type WorkflowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Workflow `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type Workflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              WorkflowSpec `json:"spec,omitempty"`
+	// Status is the v1alpha status, which already holds all of the
+	// operator's bookkeeping.
+	Status v1alpha.WorkflowStatus `json:"status,omitempty"`
+}
+
+// WorkflowSpec holds the inputs of v1alpha together with its spec, without
+// the deprecated jobBatch.
+type WorkflowSpec struct {
+	Jobs []v1alpha.Job `json:"jobs,omitempty"`
+	// RetryStrategy applies to every job that does not set its own.
+	RetryStrategy *v1alpha.RetryStrategy `json:"retryStrategy,omitempty"`
+	// ActiveDeadlineSeconds bounds how long the workflow may run once
+	// started. Running jobs are deleted and the workflow fails when exceeded.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// Suspend stops the operator from creating new jobs.
+	Suspend bool `json:"suspend,omitempty"`
+	// Cancel deletes every running job and ends the workflow as "cancelled".
+	Cancel bool `json:"cancel,omitempty"`
+	// TTLSecondsAfterFinished is how long the workflow is kept once it
+	// finished. The operator-wide retention applies when unset.
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// Priority orders the admission of queued jobs.
+	Priority int32 `json:"priority,omitempty"`
+	// PriorityClassName is the Kubernetes PriorityClass of the pods of the
+	// workflow's jobs.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Arguments are substituted into the jobs of the workflow.
+	Arguments v1alpha.Arguments `json:"arguments,omitempty"`
+	// WorkflowTemplateRef creates the workflow from a WorkflowTemplate. Jobs
+	// must then be empty.
+	WorkflowTemplateRef *v1alpha.WorkflowTemplateRef `json:"workflowTemplateRef,omitempty"`
+}
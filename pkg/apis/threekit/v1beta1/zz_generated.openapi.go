@@ -0,0 +1,196 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by openapi-gen. DO NOT EDIT.
+
+// This file was autogenerated by openapi-gen. Do not edit it manually!
+
+package v1beta1
+
+import (
+	spec "github.com/go-openapi/spec"
+	common "k8s.io/kube-openapi/pkg/common"
+)
+
+func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+	return map[string]common.OpenAPIDefinition{
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1.Workflow":     schema_pkg_apis_threekit_v1beta1_Workflow(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1.WorkflowList": schema_pkg_apis_threekit_v1beta1_WorkflowList(ref),
+		"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1.WorkflowSpec": schema_pkg_apis_threekit_v1beta1_WorkflowSpec(ref),
+	}
+}
+
+func schema_pkg_apis_threekit_v1beta1_Workflow(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1.WorkflowSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status is the v1alpha status, which already holds all of the operator's bookkeeping.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowStatus"),
+						},
+					},
+				},
+				Required: []string{"metadata"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowStatus", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1.WorkflowSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1beta1_WorkflowList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1.Workflow"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1.Workflow", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_threekit_v1beta1_WorkflowSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WorkflowSpec holds the inputs of v1alpha together with its spec, without the deprecated jobBatch.",
+				Properties: map[string]spec.Schema{
+					"jobs": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Job"),
+									},
+								},
+							},
+						},
+					},
+					"retryStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryStrategy applies to every job that does not set its own.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.RetryStrategy"),
+						},
+					},
+					"activeDeadlineSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ActiveDeadlineSeconds bounds how long the workflow may run once started. Running jobs are deleted and the workflow fails when exceeded.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"suspend": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Suspend stops the operator from creating new jobs.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"cancel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cancel deletes every running job and ends the workflow as \"cancelled\".",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"ttlSecondsAfterFinished": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TTLSecondsAfterFinished is how long the workflow is kept once it finished. The operator-wide retention applies when unset.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"priority": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Priority orders the admission of queued jobs.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"priorityClassName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PriorityClassName is the Kubernetes PriorityClass of the pods of the workflow's jobs.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"arguments": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Arguments are substituted into the jobs of the workflow.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Arguments"),
+						},
+					},
+					"workflowTemplateRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkflowTemplateRef creates the workflow from a WorkflowTemplate. Jobs must then be empty.",
+							Ref:         ref("github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateRef"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Arguments", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.Job", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.RetryStrategy", "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha.WorkflowTemplateRef"},
+	}
+}
@@ -0,0 +1,96 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package versioned
+
+import (
+	threekitv1alpha "github.com/Ziyang2go/workflowop/pkg/client/clientset/versioned/typed/threekit/v1alpha"
+	threekitv1beta1 "github.com/Ziyang2go/workflowop/pkg/client/clientset/versioned/typed/threekit/v1beta1"
+	discovery "k8s.io/client-go/discovery"
+	rest "k8s.io/client-go/rest"
+	flowcontrol "k8s.io/client-go/util/flowcontrol"
+)
+
+type Interface interface {
+	Discovery() discovery.DiscoveryInterface
+	ThreekitV1alpha() threekitv1alpha.ThreekitV1alphaInterface
+	ThreekitV1beta1() threekitv1beta1.ThreekitV1beta1Interface
+	// Deprecated: please explicitly pick a version if possible.
+	Threekit() threekitv1beta1.ThreekitV1beta1Interface
+}
+
+// Clientset contains the clients for groups. Each group has exactly one
+// version included in a Clientset.
+type Clientset struct {
+	*discovery.DiscoveryClient
+	threekitV1alpha *threekitv1alpha.ThreekitV1alphaClient
+	threekitV1beta1 *threekitv1beta1.ThreekitV1beta1Client
+}
+
+// ThreekitV1alpha retrieves the ThreekitV1alphaClient
+func (c *Clientset) ThreekitV1alpha() threekitv1alpha.ThreekitV1alphaInterface {
+	return c.threekitV1alpha
+}
+
+// ThreekitV1beta1 retrieves the ThreekitV1beta1Client
+func (c *Clientset) ThreekitV1beta1() threekitv1beta1.ThreekitV1beta1Interface {
+	return c.threekitV1beta1
+}
+
+// Deprecated: Threekit retrieves the default version of ThreekitClient.
+// Please explicitly pick a version.
+func (c *Clientset) Threekit() threekitv1beta1.ThreekitV1beta1Interface {
+	return c.threekitV1beta1
+}
+
+// Discovery retrieves the DiscoveryClient
+func (c *Clientset) Discovery() discovery.DiscoveryInterface {
+	if c == nil {
+		return nil
+	}
+	return c.DiscoveryClient
+}
+
+// NewForConfig creates a new Clientset for the given config.
+func NewForConfig(c *rest.Config) (*Clientset, error) {
+	configShallowCopy := *c
+	if configShallowCopy.RateLimiter == nil && configShallowCopy.QPS > 0 {
+		configShallowCopy.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(configShallowCopy.QPS, configShallowCopy.Burst)
+	}
+	var cs Clientset
+	var err error
+	cs.threekitV1alpha, err = threekitv1alpha.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.threekitV1beta1, err = threekitv1beta1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	return &cs, nil
+}
+
+// NewForConfigOrDie creates a new Clientset for the given config and
+// panics if there is an error in the config.
+func NewForConfigOrDie(c *rest.Config) *Clientset {
+	var cs Clientset
+	cs.threekitV1alpha = threekitv1alpha.NewForConfigOrDie(c)
+	cs.threekitV1beta1 = threekitv1beta1.NewForConfigOrDie(c)
+
+	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
+	return &cs
+}
+
+// New creates a new Clientset for the given RESTClient.
+func New(c rest.Interface) *Clientset {
+	var cs Clientset
+	cs.threekitV1alpha = threekitv1alpha.New(c)
+	cs.threekitV1beta1 = threekitv1beta1.New(c)
+
+	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
+	return &cs
+}
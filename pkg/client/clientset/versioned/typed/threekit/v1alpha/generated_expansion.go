@@ -0,0 +1,9 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha
+
+type CronWorkflowExpansion interface{}
+
+type WorkflowExpansion interface{}
+
+type WorkflowTemplateExpansion interface{}
@@ -0,0 +1,84 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha
+
+import (
+	v1alpha "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/Ziyang2go/workflowop/pkg/client/clientset/versioned/scheme"
+	serializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	rest "k8s.io/client-go/rest"
+)
+
+type ThreekitV1alphaInterface interface {
+	RESTClient() rest.Interface
+	CronWorkflowsGetter
+	WorkflowsGetter
+	WorkflowTemplatesGetter
+}
+
+// ThreekitV1alphaClient is used to interact with features provided by the threekit.com group.
+type ThreekitV1alphaClient struct {
+	restClient rest.Interface
+}
+
+func (c *ThreekitV1alphaClient) CronWorkflows(namespace string) CronWorkflowInterface {
+	return newCronWorkflows(c, namespace)
+}
+
+func (c *ThreekitV1alphaClient) Workflows(namespace string) WorkflowInterface {
+	return newWorkflows(c, namespace)
+}
+
+func (c *ThreekitV1alphaClient) WorkflowTemplates(namespace string) WorkflowTemplateInterface {
+	return newWorkflowTemplates(c, namespace)
+}
+
+// NewForConfig creates a new ThreekitV1alphaClient for the given config.
+func NewForConfig(c *rest.Config) (*ThreekitV1alphaClient, error) {
+	config := *c
+	if err := setConfigDefaults(&config); err != nil {
+		return nil, err
+	}
+	client, err := rest.RESTClientFor(&config)
+	if err != nil {
+		return nil, err
+	}
+	return &ThreekitV1alphaClient{client}, nil
+}
+
+// NewForConfigOrDie creates a new ThreekitV1alphaClient for the given config and
+// panics if there is an error in the config.
+func NewForConfigOrDie(c *rest.Config) *ThreekitV1alphaClient {
+	client, err := NewForConfig(c)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// New creates a new ThreekitV1alphaClient for the given RESTClient.
+func New(c rest.Interface) *ThreekitV1alphaClient {
+	return &ThreekitV1alphaClient{c}
+}
+
+func setConfigDefaults(config *rest.Config) error {
+	gv := v1alpha.SchemeGroupVersion
+	config.GroupVersion = &gv
+	config.APIPath = "/apis"
+	config.NegotiatedSerializer = serializer.DirectCodecFactory{CodecFactory: scheme.Codecs}
+
+	if config.UserAgent == "" {
+		config.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+
+	return nil
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *ThreekitV1alphaClient) RESTClient() rest.Interface {
+	if c == nil {
+		return nil
+	}
+	return c.restClient
+}
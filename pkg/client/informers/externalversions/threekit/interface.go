@@ -0,0 +1,38 @@
+// Code generated by informer-gen. DO NOT EDIT.
+
+package threekit
+
+import (
+	internalinterfaces "github.com/Ziyang2go/workflowop/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha "github.com/Ziyang2go/workflowop/pkg/client/informers/externalversions/threekit/v1alpha"
+	v1beta1 "github.com/Ziyang2go/workflowop/pkg/client/informers/externalversions/threekit/v1beta1"
+)
+
+// Interface provides access to each of this group's versions.
+type Interface interface {
+	// V1alpha provides access to shared informers for resources in V1alpha.
+	V1alpha() v1alpha.Interface
+	// V1beta1 provides access to shared informers for resources in V1beta1.
+	V1beta1() v1beta1.Interface
+}
+
+type group struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &group{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// V1alpha returns a new v1alpha.Interface.
+func (g *group) V1alpha() v1alpha.Interface {
+	return v1alpha.New(g.factory, g.namespace, g.tweakListOptions)
+}
+
+// V1beta1 returns a new v1beta1.Interface.
+func (g *group) V1beta1() v1beta1.Interface {
+	return v1beta1.New(g.factory, g.namespace, g.tweakListOptions)
+}
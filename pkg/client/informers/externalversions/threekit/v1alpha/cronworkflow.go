@@ -0,0 +1,73 @@
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha
+
+import (
+	time "time"
+
+	threekitv1alpha "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	versioned "github.com/Ziyang2go/workflowop/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/Ziyang2go/workflowop/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha "github.com/Ziyang2go/workflowop/pkg/client/listers/threekit/v1alpha"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// CronWorkflowInformer provides access to a shared informer and lister for
+// CronWorkflows.
+type CronWorkflowInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha.CronWorkflowLister
+}
+
+type cronWorkflowInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewCronWorkflowInformer constructs a new informer for CronWorkflow type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewCronWorkflowInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredCronWorkflowInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredCronWorkflowInformer constructs a new informer for CronWorkflow type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredCronWorkflowInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ThreekitV1alpha().CronWorkflows(namespace).List(options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ThreekitV1alpha().CronWorkflows(namespace).Watch(options)
+			},
+		},
+		&threekitv1alpha.CronWorkflow{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *cronWorkflowInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredCronWorkflowInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *cronWorkflowInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&threekitv1alpha.CronWorkflow{}, f.defaultInformer)
+}
+
+func (f *cronWorkflowInformer) Lister() v1alpha.CronWorkflowLister {
+	return v1alpha.NewCronWorkflowLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,73 @@
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha
+
+import (
+	time "time"
+
+	threekitv1alpha "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	versioned "github.com/Ziyang2go/workflowop/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/Ziyang2go/workflowop/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha "github.com/Ziyang2go/workflowop/pkg/client/listers/threekit/v1alpha"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// WorkflowInformer provides access to a shared informer and lister for
+// Workflows.
+type WorkflowInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha.WorkflowLister
+}
+
+type workflowInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewWorkflowInformer constructs a new informer for Workflow type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewWorkflowInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredWorkflowInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredWorkflowInformer constructs a new informer for Workflow type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredWorkflowInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ThreekitV1alpha().Workflows(namespace).List(options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ThreekitV1alpha().Workflows(namespace).Watch(options)
+			},
+		},
+		&threekitv1alpha.Workflow{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *workflowInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredWorkflowInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *workflowInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&threekitv1alpha.Workflow{}, f.defaultInformer)
+}
+
+func (f *workflowInformer) Lister() v1alpha.WorkflowLister {
+	return v1alpha.NewWorkflowLister(f.Informer().GetIndexer())
+}
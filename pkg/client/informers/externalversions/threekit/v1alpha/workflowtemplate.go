@@ -0,0 +1,73 @@
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha
+
+import (
+	time "time"
+
+	threekitv1alpha "github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	versioned "github.com/Ziyang2go/workflowop/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/Ziyang2go/workflowop/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha "github.com/Ziyang2go/workflowop/pkg/client/listers/threekit/v1alpha"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// WorkflowTemplateInformer provides access to a shared informer and lister for
+// WorkflowTemplates.
+type WorkflowTemplateInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha.WorkflowTemplateLister
+}
+
+type workflowTemplateInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewWorkflowTemplateInformer constructs a new informer for WorkflowTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewWorkflowTemplateInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredWorkflowTemplateInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredWorkflowTemplateInformer constructs a new informer for WorkflowTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredWorkflowTemplateInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ThreekitV1alpha().WorkflowTemplates(namespace).List(options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ThreekitV1alpha().WorkflowTemplates(namespace).Watch(options)
+			},
+		},
+		&threekitv1alpha.WorkflowTemplate{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *workflowTemplateInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredWorkflowTemplateInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *workflowTemplateInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&threekitv1alpha.WorkflowTemplate{}, f.defaultInformer)
+}
+
+func (f *workflowTemplateInformer) Lister() v1alpha.WorkflowTemplateLister {
+	return v1alpha.NewWorkflowTemplateLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,27 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha
+
+// CronWorkflowListerExpansion allows custom methods to be added to
+// CronWorkflowLister.
+type CronWorkflowListerExpansion interface{}
+
+// CronWorkflowNamespaceListerExpansion allows custom methods to be added to
+// CronWorkflowNamespaceLister.
+type CronWorkflowNamespaceListerExpansion interface{}
+
+// WorkflowListerExpansion allows custom methods to be added to
+// WorkflowLister.
+type WorkflowListerExpansion interface{}
+
+// WorkflowNamespaceListerExpansion allows custom methods to be added to
+// WorkflowNamespaceLister.
+type WorkflowNamespaceListerExpansion interface{}
+
+// WorkflowTemplateListerExpansion allows custom methods to be added to
+// WorkflowTemplateLister.
+type WorkflowTemplateListerExpansion interface{}
+
+// WorkflowTemplateNamespaceListerExpansion allows custom methods to be added to
+// WorkflowTemplateNamespaceLister.
+type WorkflowTemplateNamespaceListerExpansion interface{}
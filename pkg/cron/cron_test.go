@@ -0,0 +1,123 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"1-x * * * *",
+		"* * * foo *",
+		"* * * * monday",
+		"@every 5m",
+	}
+	for _, spec := range tests {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// A Monday.
+	from := time.Date(2018, 9, 3, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2018, 9, 3, 10, 8, 0, 0, time.UTC)},
+		{"step", "*/15 * * * *", time.Date(2018, 9, 3, 10, 15, 0, 0, time.UTC)},
+		{"range with step", "5-10/2 * * * *", time.Date(2018, 9, 3, 10, 9, 0, 0, time.UTC)},
+		{"value with step", "10/20 * * * *", time.Date(2018, 9, 3, 10, 10, 0, 0, time.UTC)},
+		{"list and range", "0,30 9-17 * * *", time.Date(2018, 9, 3, 10, 30, 0, 0, time.UTC)},
+		{"list of ranges", "0 1-3,22-23 * * *", time.Date(2018, 9, 3, 22, 0, 0, 0, time.UTC)},
+		{"next day", "0 0 * * *", time.Date(2018, 9, 4, 0, 0, 0, 0, time.UTC)},
+		{"shorthand", "@hourly", time.Date(2018, 9, 3, 11, 0, 0, 0, time.UTC)},
+		{"shorthand in upper case", "@Monthly", time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month", "0 12 1 * *", time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)},
+		{"month name", "0 0 1 jan *", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"month name range", "0 0 1 NOV-dec *", time.Date(2018, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of week", "0 0 * * 0", time.Date(2018, 9, 9, 0, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", time.Date(2018, 9, 9, 0, 0, 0, 0, time.UTC)},
+		{"weekdays", "0 0 * * mon-fri", time.Date(2018, 9, 4, 0, 0, 0, 0, time.UTC)},
+		{"weekend list", "0 0 * * sat,sun", time.Date(2018, 9, 8, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches, so the Friday comes
+		// before the 13th.
+		{"day of month or day of week", "0 0 13 * fri", time.Date(2018, 9, 7, 0, 0, 0, 0, time.UTC)},
+		{"day of month or day of week, day of month first", "0 0 5 * sun", time.Date(2018, 9, 5, 0, 0, 0, 0, time.UTC)},
+		// A day field starting with * does not count as restricted, even
+		// with a step: both must match.
+		{"stepped star day of month and day of week", "0 0 */10 * mon", time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month and star day of week", "0 0 15 * *", time.Date(2018, 9, 15, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.spec, err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextIsAfter(t *testing.T) {
+	s, err := Parse("30 10 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2018, 9, 3, 10, 30, 0, 0, time.UTC)
+	if got, want := s.Next(at), at.AddDate(0, 0, 1); !got.Equal(want) {
+		t.Errorf("Next(%s) = %s, want %s", at, got, want)
+	}
+}
+
+func TestNextDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		// 02:30 does not exist on the day clocks move forward.
+		{"skipped time", "30 2 * * *", time.Date(2019, 3, 10, 0, 0, 0, 0, loc), time.Date(2019, 3, 11, 2, 30, 0, 0, loc)},
+		{"hour after the change", "0 3 * * *", time.Date(2019, 3, 10, 0, 0, 0, 0, loc), time.Date(2019, 3, 10, 3, 0, 0, 0, loc)},
+		{"local midnight", "0 0 * * *", time.Date(2019, 11, 2, 12, 0, 0, 0, loc), time.Date(2019, 11, 3, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.spec, err)
+			}
+			got := s.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want)
+			}
+			if got.Location() != loc {
+				t.Errorf("Next(%s) is in %s, want %s", tt.from, got.Location(), loc)
+			}
+		})
+	}
+}
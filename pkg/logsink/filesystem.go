@@ -0,0 +1,44 @@
+package logsink
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type filesystem struct {
+	dir string
+}
+
+// NewFilesystem returns a Sink writing logs below dir, typically a mounted
+// persistent volume.
+func NewFilesystem(dir string) Sink {
+	return &filesystem{dir}
+}
+
+func (f *filesystem) Put(key string, r io.Reader) (*Object, error) {
+	path := filepath.Join(f.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return &Object{
+		URL:      "file://" + filepath.ToSlash(path),
+		Size:     size,
+		Checksum: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
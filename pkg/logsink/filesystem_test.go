@@ -0,0 +1,65 @@
+package logsink
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilesystemPut(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logsink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	const log = "step 1\nstep 2\n"
+	sum := sha256.Sum256([]byte(log))
+
+	obj, err := NewFilesystem(dir).Put("default/render-1/export.log", strings.NewReader(log))
+	if err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	path := filepath.Join(dir, "default", "render-1", "export.log")
+	want := Object{
+		URL:      "file://" + filepath.ToSlash(path),
+		Size:     int64(len(log)),
+		Checksum: hex.EncodeToString(sum[:]),
+	}
+	if *obj != want {
+		t.Errorf("Put() = %+v, want %+v", *obj, want)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != log {
+		t.Errorf("stored %q, want %q", data, log)
+	}
+
+	// A second attempt of the same job replaces the log.
+	if _, err := NewFilesystem(dir).Put("default/render-1/export.log", strings.NewReader("retry\n")); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "retry\n" {
+		t.Errorf("stored %q after the second Put, want %q", data, "retry\n")
+	}
+}
+
+func TestFilesystemPutError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logsink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// A file where the directory of the key should be.
+	if err := ioutil.WriteFile(filepath.Join(dir, "default"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFilesystem(dir).Put("default/render-1/export.log", strings.NewReader("log")); err == nil {
+		t.Error("Put() succeeded, want an error")
+	}
+}
@@ -0,0 +1,62 @@
+package logsink
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// Sink archives complete job logs outside of the cluster.
+type Sink interface {
+	// Put stores everything read from r under key and describes where the
+	// stored copy can be found.
+	Put(key string, r io.Reader) (*Object, error)
+}
+
+// Object describes an archived log.
+type Object struct {
+	URL string
+	// Size is the length of the log in bytes.
+	Size int64
+	// Checksum is the hex encoded SHA-256 of the log.
+	Checksum string
+}
+
+// FromEnv builds the sink selected by the LOG_SINK environment variable:
+//
+//	file: LOG_SINK_DIR
+//	s3:   S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY_ID,
+//	      S3_SECRET_ACCESS_KEY, S3_PATH_STYLE
+//
+// It returns a nil Sink when LOG_SINK is empty.
+func FromEnv() (Sink, error) {
+	switch kind := os.Getenv("LOG_SINK"); kind {
+	case "":
+		return nil, nil
+	case "file":
+		dir := os.Getenv("LOG_SINK_DIR")
+		if dir == "" {
+			return nil, fmt.Errorf("LOG_SINK_DIR is required for the file log sink")
+		}
+		return NewFilesystem(dir), nil
+	case "s3":
+		config := S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		}
+		if pathStyle := os.Getenv("S3_PATH_STYLE"); pathStyle != "" {
+			v, err := strconv.ParseBool(pathStyle)
+			if err != nil {
+				return nil, fmt.Errorf("invalid S3_PATH_STYLE %q: %v", pathStyle, err)
+			}
+			config.PathStyle = v
+		}
+		return NewS3(config)
+	default:
+		return nil, fmt.Errorf("unknown log sink %q", kind)
+	}
+}
@@ -10,9 +10,10 @@ import (
 )
 
 type MongoSVC interface {
-	Create(string, string, string, string) error
-	Update(string, string, string) error
-	Get(string) Job
+	Create(JobKey, string, string, string, string) error
+	Update(JobKey, string, string) error
+	Finish(JobKey, string, string) error
+	Get(JobKey) Job
 	Close() error
 }
 
@@ -26,6 +27,16 @@ func New(host, port, dbName string, collectionName string) (MongoSVC, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Sparse, so that records written before jobs were keyed by attempt do
+	// not collide.
+	err = db.DB(dbName).C(collectionName).EnsureIndex(mgo.Index{
+		Key:    []string{"namespace", "workflow", "workflowuid", "job", "attempt"},
+		Unique: true,
+		Sparse: true,
+	})
+	if err != nil {
+		return nil, err
+	}
 	return &mongo{db, dbName, collectionName}, nil
 }
 
@@ -34,10 +45,11 @@ func (m *mongo) Close() error {
 	return nil
 }
 
-func (m *mongo) Create(name string, status string, org string, jobType string) error {
+func (m *mongo) Create(key JobKey, name string, status string, org string, jobType string) error {
 	log.Print("Creating mongo record.....")
 	log.Print(name, status)
-	job := &Job{bson.NewObjectId(), name, status, "", org, jobType, time.Now()}
+	now := time.Now()
+	job := &Job{ID: bson.NewObjectId(), JobKey: key, NAME: name, STATUS: status, ORGANIZATION: org, TYPE: jobType, CREATEDAT: now, UPDATEDAT: now}
 	c := m.db.DB(m.dbName).C(m.collectionName)
 	err := c.Insert(job)
 	if err != nil {
@@ -46,21 +58,32 @@ func (m *mongo) Create(name string, status string, org string, jobType string) e
 	return nil
 }
 
-func (m *mongo) Get(name string) Job {
-	log.Printf("Get instance %s", name)
+func (m *mongo) Get(key JobKey) Job {
+	log.Printf("Get instance %s", key)
 	c := m.db.DB(m.dbName).C(m.collectionName)
 	var data Job
-	err := c.Find(bson.M{"name": name}).One(&data)
+	err := c.Find(key.selector()).One(&data)
 	if err != nil {
-		fmt.Printf("Could not get doc %s ", name)
+		fmt.Printf("Could not get doc %s ", key)
 	}
 	return data
 }
 
-func (m *mongo) Update(name string, status string, jobLog string) error {
-	log.Printf("Update job instance %s %s", name, status)
+func (m *mongo) Update(key JobKey, status string, jobLog string) error {
+	log.Printf("Update job instance %s %s", key, status)
 	c := m.db.DB(m.dbName).C(m.collectionName)
-	err := c.Update(bson.M{"name": name}, bson.M{"$set": bson.M{"status": status, "logs": jobLog}})
+	err := c.Update(key.selector(), bson.M{"$set": bson.M{"status": status, "logs": jobLog, "updatedat": time.Now()}})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mongo) Finish(key JobKey, status string, jobLog string) error {
+	log.Printf("Finish job instance %s %s", key, status)
+	c := m.db.DB(m.dbName).C(m.collectionName)
+	now := time.Now()
+	err := c.Update(key.selector(), bson.M{"$set": bson.M{"status": status, "logs": jobLog, "updatedat": now, "finishedat": now}})
 	if err != nil {
 		return err
 	}
@@ -73,6 +96,25 @@ type mongo struct {
 	collectionName string
 }
 
+// JobKey identifies one attempt of a job of a workflow. Batch Job names are
+// reused when a workflow is submitted again under the same name, so records
+// are keyed by the workflow UID and the attempt.
+type JobKey struct {
+	Namespace   string `json:"namespace"`
+	Workflow    string `json:"workflow"`
+	WorkflowUID string `json:"workflowUID"`
+	Job         string `json:"job"`
+	Attempt     int    `json:"attempt"`
+}
+
+func (k JobKey) String() string {
+	return fmt.Sprintf("%s/%s (%s) job %s attempt %d", k.Namespace, k.Workflow, k.WorkflowUID, k.Job, k.Attempt)
+}
+
+func (k JobKey) selector() bson.M {
+	return bson.M{"namespace": k.Namespace, "workflow": k.Workflow, "workflowuid": k.WorkflowUID, "job": k.Job, "attempt": k.Attempt}
+}
+
 type Job struct {
 	ID           bson.ObjectId `json:"_id" bson:"_id"`
 	NAME         string        `json:"name"`
@@ -81,4 +123,8 @@ type Job struct {
 	ORGANIZATION string        `json:"organization"`
 	TYPE         string        `json:"type"`
 	CREATEDAT    time.Time     `json:"createdAt"`
+	UPDATEDAT    time.Time     `json:"updatedAt"`
+	FINISHEDAT   *time.Time    `json:"finishedAt,omitempty" bson:",omitempty"`
+
+	JobKey `bson:",inline"`
 }
@@ -0,0 +1,278 @@
+package quota
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PolicyKey is the ConfigMap data key holding the YAML or JSON Policy.
+const PolicyKey = "policy"
+
+// DefaultMaxJobs caps the number of running jobs when no policy is loaded.
+const DefaultMaxJobs = 1000
+
+// Policy limits how many jobs may run at the same time.
+type Policy struct {
+	// MaxJobs caps the running jobs of all organizations together.
+	MaxJobs int `json:"maxJobs"`
+	// Default applies to organizations without their own entry.
+	Default Limits `json:"default"`
+	// Organizations holds per organization limits, keyed by the value of the
+	// workflow organization label.
+	Organizations map[string]Limits `json:"organizations,omitempty"`
+}
+
+// Limits are the quotas of one organization. A zero limit means unlimited.
+type Limits struct {
+	MaxJobs        int            `json:"maxJobs,omitempty"`
+	MaxJobsPerType map[string]int `json:"maxJobsPerType,omitempty"`
+	// Weight is the share of free capacity the organization gets relative to
+	// other organizations with queued jobs. Defaults to 1.
+	Weight int `json:"weight,omitempty"`
+}
+
+// Ticket identifies a job waiting to be admitted.
+type Ticket struct {
+	// Key uniquely identifies the job, e.g. namespace/workflow/job.
+	Key          string
+	Organization string
+	Type         string
+	// Priority admits tickets of higher priority first, whatever their
+	// organization.
+	Priority int32
+	// Created orders the tickets of the same priority of one organization,
+	// oldest first.
+	Created time.Time
+}
+
+// Usage counts the jobs currently running.
+type Usage struct {
+	Total     int
+	ByOrg     map[string]int
+	ByOrgType map[string]map[string]int
+}
+
+// NewUsage returns an empty Usage.
+func NewUsage() Usage {
+	return Usage{
+		ByOrg:     make(map[string]int),
+		ByOrgType: make(map[string]map[string]int),
+	}
+}
+
+// Add counts one running job.
+func (u *Usage) Add(org, jobType string) {
+	u.Total++
+	u.ByOrg[org]++
+	if u.ByOrgType[org] == nil {
+		u.ByOrgType[org] = make(map[string]int)
+	}
+	u.ByOrgType[org][jobType]++
+}
+
+type waiting struct {
+	ticket   Ticket
+	lastSeen time.Time
+}
+
+// Scheduler admits jobs within the quota policy. Jobs over quota wait in a
+// queue and, when capacity frees, are admitted by decreasing priority. Within
+// a priority organizations are served in weighted round-robin order and the
+// jobs of one organization oldest first.
+type Scheduler struct {
+	mu      sync.Mutex
+	policy  Policy
+	waiting map[string]*waiting
+	// pass is the virtual time of each organization in the stride
+	// scheduling of queued jobs: it advances by 1/weight on every admission.
+	pass map[string]float64
+	// staleAfter drops queued tickets that were not asked for again, e.g.
+	// because their workflow was deleted.
+	staleAfter time.Duration
+	now        func() time.Time
+}
+
+// NewScheduler returns a Scheduler with the default policy. Tickets that are
+// not asked for again within staleAfter are forgotten.
+func NewScheduler(staleAfter time.Duration) *Scheduler {
+	return &Scheduler{
+		policy:     Policy{MaxJobs: DefaultMaxJobs},
+		waiting:    make(map[string]*waiting),
+		pass:       make(map[string]float64),
+		staleAfter: staleAfter,
+		now:        time.Now,
+	}
+}
+
+// Load replaces the policy with the one stored in cm.
+func (s *Scheduler) Load(cm *corev1.ConfigMap) error {
+	policy := Policy{MaxJobs: DefaultMaxJobs}
+	if data, found := cm.Data[PolicyKey]; found {
+		if err := yaml.Unmarshal([]byte(data), &policy); err != nil {
+			return fmt.Errorf("invalid quota policy: %v", err)
+		}
+	}
+	s.mu.Lock()
+	s.policy = policy
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Scheduler) limits(org string) Limits {
+	if limits, found := s.policy.Organizations[org]; found {
+		return limits
+	}
+	return s.policy.Default
+}
+
+// fits reports whether one more job of ticket fits in the quotas of its
+// organization.
+func (s *Scheduler) fits(t Ticket, usage Usage) bool {
+	limits := s.limits(t.Organization)
+	if limits.MaxJobs > 0 && usage.ByOrg[t.Organization] >= limits.MaxJobs {
+		return false
+	}
+	if max := limits.MaxJobsPerType[t.Type]; max > 0 && usage.ByOrgType[t.Organization][t.Type] >= max {
+		return false
+	}
+	return true
+}
+
+// Admit reports whether the job of ticket may be created now, given the jobs
+// currently running. When it may not, the ticket is queued and the caller
+// should ask again later.
+func (s *Scheduler) Admit(t Ticket, usage Usage) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for key, w := range s.waiting {
+		if now.Sub(w.lastSeen) > s.staleAfter {
+			delete(s.waiting, key)
+		}
+	}
+	if w, found := s.waiting[t.Key]; found {
+		w.lastSeen = now
+	} else {
+		s.pass[t.Organization] = s.minPass(t.Organization)
+		s.waiting[t.Key] = &waiting{ticket: t, lastSeen: now}
+	}
+	if !s.fits(t, usage) {
+		return false
+	}
+	free := DefaultMaxJobs
+	if s.policy.MaxJobs > 0 {
+		free = s.policy.MaxJobs
+	}
+	free -= usage.Total
+	if free <= 0 {
+		return false
+	}
+	if rank := s.rank(t, usage); rank < 0 || rank >= free {
+		return false
+	}
+	delete(s.waiting, t.Key)
+	weight := s.limits(t.Organization).Weight
+	if weight <= 0 {
+		weight = 1
+	}
+	s.pass[t.Organization] += 1 / float64(weight)
+	return true
+}
+
+// minPass keeps an organization that had nothing queued for a while from
+// claiming all the capacity it missed: when it queues a ticket again, its pass
+// is raised to the smallest pass of the organizations already queued.
+func (s *Scheduler) minPass(org string) float64 {
+	pass := s.pass[org]
+	first := true
+	var min float64
+	for _, w := range s.waiting {
+		if w.ticket.Organization == org {
+			return pass
+		}
+		p := s.pass[w.ticket.Organization]
+		if first || p < min {
+			min, first = p, false
+		}
+	}
+	if !first && pass < min {
+		return min
+	}
+	return pass
+}
+
+// rank returns the position of ticket in the order queued tickets that fit in
+// their quotas would be admitted in, or -1 if the ticket is not queued.
+func (s *Scheduler) rank(t Ticket, usage Usage) int {
+	levels := make(map[int32][]Ticket)
+	for _, w := range s.waiting {
+		if s.fits(w.ticket, usage) {
+			levels[w.ticket.Priority] = append(levels[w.ticket.Priority], w.ticket)
+		}
+	}
+	priorities := make([]int32, 0, len(levels))
+	for priority := range levels {
+		priorities = append(priorities, priority)
+	}
+	sort.Slice(priorities, func(i, j int) bool { return priorities[i] > priorities[j] })
+	pass := make(map[string]float64, len(s.pass))
+	for org, p := range s.pass {
+		pass[org] = p
+	}
+	rank := 0
+	for _, priority := range priorities {
+		if r := s.rankLevel(t, levels[priority], pass); r >= 0 {
+			return rank + r
+		}
+		rank += len(levels[priority])
+	}
+	return -1
+}
+
+// rankLevel orders the tickets of one priority in weighted round-robin order
+// of their organizations, advancing pass for every ticket ranked. It returns
+// the position of t, or -1 if t is not among tickets.
+func (s *Scheduler) rankLevel(t Ticket, tickets []Ticket, pass map[string]float64) int {
+	queues := make(map[string][]Ticket)
+	for _, ticket := range tickets {
+		queues[ticket.Organization] = append(queues[ticket.Organization], ticket)
+	}
+	orgs := make([]string, 0, len(queues))
+	for org, queue := range queues {
+		sort.Slice(queue, func(i, j int) bool {
+			if !queue[i].Created.Equal(queue[j].Created) {
+				return queue[i].Created.Before(queue[j].Created)
+			}
+			return queue[i].Key < queue[j].Key
+		})
+		orgs = append(orgs, org)
+	}
+	sort.Strings(orgs)
+	for rank := 0; len(orgs) > 0; rank++ {
+		next := 0
+		for i, org := range orgs {
+			if pass[org] < pass[orgs[next]] {
+				next = i
+			}
+		}
+		org := orgs[next]
+		if queues[org][0].Key == t.Key {
+			return rank
+		}
+		queues[org] = queues[org][1:]
+		weight := s.limits(org).Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		pass[org] += 1 / float64(weight)
+		if len(queues[org]) == 0 {
+			orgs = append(orgs[:next], orgs[next+1:]...)
+		}
+	}
+	return -1
+}
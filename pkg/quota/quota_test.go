@@ -0,0 +1,224 @@
+package quota
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+var testNow = time.Date(2018, 9, 3, 12, 0, 0, 0, time.UTC)
+
+func newTestScheduler(policy Policy) *Scheduler {
+	s := NewScheduler(time.Minute)
+	s.policy = policy
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+// usage counts running jobs given as organization/type.
+func usage(jobs ...string) Usage {
+	u := NewUsage()
+	for _, job := range jobs {
+		parts := strings.SplitN(job, "/", 2)
+		u.Add(parts[0], parts[1])
+	}
+	return u
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    Policy
+		wantErr bool
+	}{
+		{name: "no policy", want: Policy{MaxJobs: DefaultMaxJobs}},
+		{
+			name: "yaml",
+			data: map[string]string{PolicyKey: "maxJobs: 10\ndefault:\n  maxJobs: 2\norganizations:\n  acme:\n    maxJobs: 5\n    weight: 3\n    maxJobsPerType:\n      render: 1\n"},
+			want: Policy{
+				MaxJobs: 10,
+				Default: Limits{MaxJobs: 2},
+				Organizations: map[string]Limits{
+					"acme": {MaxJobs: 5, Weight: 3, MaxJobsPerType: map[string]int{"render": 1}},
+				},
+			},
+		},
+		{name: "json without total", data: map[string]string{PolicyKey: `{"default":{"maxJobs":2}}`}, want: Policy{MaxJobs: DefaultMaxJobs, Default: Limits{MaxJobs: 2}}},
+		{name: "invalid", data: map[string]string{PolicyKey: "maxJobs: [10"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(time.Minute)
+			err := s.Load(&corev1.ConfigMap{Data: tt.data})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := fmt.Sprintf("%+v", s.policy), fmt.Sprintf("%+v", tt.want); got != want {
+				t.Errorf("policy = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestAdmitLimits(t *testing.T) {
+	policy := Policy{
+		MaxJobs: 4,
+		Default: Limits{MaxJobs: 2},
+		Organizations: map[string]Limits{
+			"acme":  {MaxJobsPerType: map[string]int{"render": 1}},
+			"small": {MaxJobs: 1},
+		},
+	}
+	tests := []struct {
+		name   string
+		ticket Ticket
+		usage  Usage
+		want   bool
+	}{
+		{name: "idle", ticket: Ticket{Key: "a", Organization: "other", Type: "render"}, usage: usage(), want: true},
+		{name: "default limit", ticket: Ticket{Key: "a", Organization: "other", Type: "render"}, usage: usage("other/render", "other/export"), want: false},
+		{name: "default limit of another organization", ticket: Ticket{Key: "a", Organization: "other", Type: "render"}, usage: usage("third/render", "third/render"), want: true},
+		{name: "organization limit", ticket: Ticket{Key: "a", Organization: "small", Type: "render"}, usage: usage("small/export"), want: false},
+		{name: "type limit", ticket: Ticket{Key: "a", Organization: "acme", Type: "render"}, usage: usage("acme/render"), want: false},
+		{name: "other type", ticket: Ticket{Key: "a", Organization: "acme", Type: "export"}, usage: usage("acme/render", "acme/export", "acme/export"), want: true},
+		{name: "total limit", ticket: Ticket{Key: "a", Organization: "acme", Type: "export"}, usage: usage("acme/export", "b/x", "c/x", "d/x"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTestScheduler(policy).Admit(tt.ticket, tt.usage); got != tt.want {
+				t.Errorf("Admit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// admitted fills the capacity of s with tickets, then frees one job at a time
+// and returns the keys of the tickets admitted, in order.
+func admitted(s *Scheduler, tickets []Ticket) []string {
+	full := usage()
+	full.Total = s.policy.MaxJobs
+	for _, t := range tickets {
+		if s.Admit(t, full) {
+			return []string{"admitted " + t.Key + " while full"}
+		}
+	}
+	oneFree := usage()
+	oneFree.Total = s.policy.MaxJobs - 1
+	var keys []string
+	for len(tickets) > 0 {
+		admittedOne := false
+		for i, t := range tickets {
+			if s.Admit(t, oneFree) {
+				keys = append(keys, t.Key)
+				tickets = append(tickets[:i], tickets[i+1:]...)
+				admittedOne = true
+				break
+			}
+		}
+		if !admittedOne {
+			return append(keys, "none")
+		}
+	}
+	return keys
+}
+
+func tickets(org string, n int, priority int32) []Ticket {
+	var ts []Ticket
+	for i := 0; i < n; i++ {
+		ts = append(ts, Ticket{
+			Key:          fmt.Sprintf("%s-%d", org, i),
+			Organization: org,
+			Type:         "render",
+			Priority:     priority,
+			// Created last first, so that the order of the tickets does not
+			// decide.
+			Created: testNow.Add(-time.Duration(i) * time.Second),
+		})
+	}
+	return ts
+}
+
+func TestAdmitOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[string]int
+		tickets []Ticket
+		want    []string
+	}{
+		{name: "oldest first", tickets: tickets("a", 3, 0), want: []string{"a-2", "a-1", "a-0"}},
+		{
+			name:    "higher priority first",
+			tickets: append(tickets("a", 2, 0), tickets("b", 2, 5)...),
+			want:    []string{"b-1", "b-0", "a-1", "a-0"},
+		},
+		{
+			name:    "round-robin",
+			tickets: append(tickets("a", 3, 0), tickets("b", 3, 0)...),
+			want:    []string{"a-2", "b-2", "a-1", "b-1", "a-0", "b-0"},
+		},
+		{
+			name:    "weighted round-robin",
+			weights: map[string]int{"a": 2},
+			tickets: append(tickets("a", 4, 0), tickets("b", 2, 0)...),
+			want:    []string{"a-3", "b-1", "a-2", "a-1", "b-0", "a-0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := Policy{MaxJobs: 10, Organizations: make(map[string]Limits)}
+			for org, weight := range tt.weights {
+				policy.Organizations[org] = Limits{Weight: weight}
+			}
+			got := admitted(newTestScheduler(policy), tt.tickets)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("admitted %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// An organization that had nothing queued does not get the capacity it did
+// not use ahead of the organizations that kept running jobs.
+func TestAdmitIdleOrganization(t *testing.T) {
+	s := newTestScheduler(Policy{MaxJobs: 10})
+	for _, t := range tickets("a", 5, 0) {
+		s.Admit(t, usage())
+	}
+	var got []string
+	for _, key := range admitted(s, append(tickets("a", 3, 0), tickets("b", 3, 0)...)) {
+		got = append(got, key[:1])
+	}
+	if want := "a b a b a b"; strings.Join(got, " ") != want {
+		t.Errorf("admitted jobs of %v, want %s", got, want)
+	}
+}
+
+func TestAdmitForgetsStaleTickets(t *testing.T) {
+	s := newTestScheduler(Policy{MaxJobs: 1})
+	now := testNow
+	s.now = func() time.Time { return now }
+	if s.Admit(Ticket{Key: "high", Organization: "a", Priority: 10}, usage("a/render")) {
+		t.Fatal("Admit() = true while full")
+	}
+	low := Ticket{Key: "low", Organization: "b"}
+	if s.Admit(low, usage()) {
+		t.Error("Admit() = true ahead of a queued ticket of higher priority")
+	}
+	// Only low is asked for again.
+	now = now.Add(45 * time.Second)
+	s.Admit(low, usage("a/render"))
+	now = now.Add(45 * time.Second)
+	if !s.Admit(low, usage()) {
+		t.Error("Admit() = false behind a ticket that was not asked for again")
+	}
+	if len(s.waiting) != 0 {
+		t.Errorf("%d tickets still queued, want none", len(s.waiting))
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
 	operator "github.com/Ziyang2go/workflowop/pkg/workflow"
 	"github.com/operator-framework/operator-sdk/pkg/sdk"
-	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func NewHandler(workflowop operator.WorkflowOpMethod) sdk.Handler {
@@ -22,9 +22,17 @@ type Handler struct {
 func (h *Handler) Handle(ctx context.Context, event sdk.Event) error {
 	switch o := event.Object.(type) {
 	case *v1alpha.Workflow:
-		h.operator.HandleWorkflow(o)
-	case *batchv1.Job:
-		h.operator.HandleJob(o)
+		if !event.Deleted {
+			h.operator.HandleWorkflow(o)
+		}
+	case *v1alpha.CronWorkflow:
+		if !event.Deleted {
+			h.operator.HandleCronWorkflow(o)
+		}
+	case *corev1.ConfigMap:
+		if !event.Deleted {
+			h.operator.HandleConfigMap(o)
+		}
 	}
 	return nil
 }
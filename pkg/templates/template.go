@@ -1,17 +1,127 @@
 package template
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/ghodss/yaml"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
-func GetJobTemplate(jobType, jobName, jobData string, o *v1alpha.Workflow) *batchv1.Job {
+// Labels set on every batch Job created for a workflow job.
+const (
+	WorkflowLabel = "threekit.com/workflow"
+	JobLabel      = "threekit.com/job"
+	JobTypeLabel  = "threekit.com/job-type"
+)
+
+// JobType describes the container that runs every job of a given
+// v1alpha.Job.Type. It is read from one key of the job type ConfigMap.
+type JobType struct {
+	Image              string                      `json:"image"`
+	Command            []string                    `json:"command,omitempty"`
+	Args               []string                    `json:"args,omitempty"`
+	Env                []corev1.EnvVar             `json:"env,omitempty"`
+	Resources          corev1.ResourceRequirements `json:"resources,omitempty"`
+	ServiceAccountName string                      `json:"serviceAccountName,omitempty"`
+	// OutputPath is the file the container writes its JSON result to. It
+	// becomes the termination message read by the operator, by default
+	// /dev/termination-log.
+	OutputPath string `json:"outputPath,omitempty"`
+}
+
+// UnknownJobTypeError is returned when a job refers to a type that is not
+// present in the registry.
+type UnknownJobTypeError struct {
+	Type string
+}
+
+func (e *UnknownJobTypeError) Error() string {
+	return fmt.Sprintf("unknown job type %q", e.Type)
+}
+
+// IsUnknownJobType reports whether err is an UnknownJobTypeError.
+func IsUnknownJobType(err error) bool {
+	_, ok := err.(*UnknownJobTypeError)
+	return ok
+}
+
+// Registry maps job type names to their container specs. It is safe for
+// concurrent use and can be reloaded while the operator is running.
+type Registry struct {
+	mu    sync.RWMutex
+	types map[string]JobType
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		types: make(map[string]JobType),
+	}
+}
+
+// Load replaces the registry content with the job types defined in cm. Each
+// data key is a job type name and its value a YAML or JSON JobType. Nothing
+// is replaced if any entry is invalid.
+func (r *Registry) Load(cm *corev1.ConfigMap) error {
+	types := make(map[string]JobType, len(cm.Data))
+	for name, spec := range cm.Data {
+		var jobType JobType
+		if err := yaml.Unmarshal([]byte(spec), &jobType); err != nil {
+			return fmt.Errorf("invalid job type %s: %v", name, err)
+		}
+		if jobType.Image == "" {
+			return fmt.Errorf("invalid job type %s: image is required", name)
+		}
+		types[name] = jobType
+	}
+	r.mu.Lock()
+	r.types = types
+	r.mu.Unlock()
+	return nil
+}
+
+// Get returns the job type registered under name.
+func (r *Registry) Get(name string) (JobType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	jobType, found := r.types[name]
+	if !found {
+		return JobType{}, &UnknownJobTypeError{Type: name}
+	}
+	return jobType, nil
+}
+
+// JobLabels returns the labels of the batch Job named jobName for a workflow
+// job.
+func JobLabels(job v1alpha.Job, jobName string, o *v1alpha.Workflow) map[string]string {
 	labels := map[string]string{
-		"name": jobName,
+		"name":        jobName,
+		WorkflowLabel: o.Name,
+		JobLabel:      job.Name,
+		JobTypeLabel:  job.Type,
 	}
+	if org, found := o.GetLabels()[v1alpha.OrganizationLabel]; found {
+		labels[v1alpha.OrganizationLabel] = org
+	}
+	return labels
+}
+
+// GetJobTemplate builds the batch Job named jobName running jobType for a
+// workflow job. The job data is handed to the container in the JOB_DATA
+// environment variable. The Job never retries failed pods itself: retries are
+// driven by the operator.
+func GetJobTemplate(jobType JobType, job v1alpha.Job, jobName string, o *v1alpha.Workflow) *batchv1.Job {
+	labels := JobLabels(job, jobName, o)
+	env := append([]corev1.EnvVar{
+		{Name: "WORKFLOW_NAME", Value: o.Name},
+		{Name: "JOB_NAME", Value: jobName},
+		{Name: "JOB_DATA", Value: job.Data},
+	}, jobType.Env...)
+	backoffLimit := int32(0)
 	return &batchv1.Job{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Job",
@@ -19,7 +129,7 @@ func GetJobTemplate(jobType, jobName, jobData string, o *v1alpha.Workflow) *batc
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      jobName,
-			Namespace: "default",
+			Namespace: o.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(o, schema.GroupVersionKind{
 					Group:   v1alpha.SchemeGroupVersion.Group,
@@ -30,14 +140,21 @@ func GetJobTemplate(jobType, jobName, jobData string, o *v1alpha.Workflow) *batc
 			Labels: labels,
 		},
 		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					RestartPolicy: "Never",
+					RestartPolicy:      "Never",
+					ServiceAccountName: jobType.ServiceAccountName,
+					PriorityClassName:  o.Spec.PriorityClassName,
 					Containers: []corev1.Container{
 						{
-							Name:    "pi",
-							Image:   "perl",
-							Command: []string{"perl", "-Mbignum=bpi", "-wle", "print bpi(2000)"},
+							Name:                   "job",
+							Image:                  jobType.Image,
+							Command:                jobType.Command,
+							Args:                   jobType.Args,
+							Env:                    env,
+							Resources:              jobType.Resources,
+							TerminationMessagePath: jobType.OutputPath,
 						},
 					},
 				},
@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/operator-framework/operator-sdk/pkg/k8sclient"
+	"github.com/sirupsen/logrus"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/cert"
+)
+
+// Config describes how the API server reaches the webhook.
+type Config struct {
+	// Namespace and ServiceName name the Service in front of the operator.
+	Namespace   string
+	ServiceName string
+	// Port is the port the webhook listens on.
+	Port int
+	// ConfigurationName is the name of the ValidatingWebhookConfiguration and
+	// MutatingWebhookConfiguration registered for the webhook.
+	ConfigurationName string
+}
+
+// Start creates a self-signed certificate authority and serving certificate
+// for the webhook Service, registers the webhook with the CA bundle and
+// serves it in the background. The certificates live in memory only and are
+// replaced, together with the CA bundle, every time the operator starts.
+func Start(client kubernetes.Interface, config Config) error {
+	serving, caPEM, err := newCertificates(config)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook certificates: %v", err)
+	}
+	if err := register(client, config, caPEM); err != nil {
+		return fmt.Errorf("failed to register webhook: %v", err)
+	}
+	if err := registerConversion(config, caPEM); err != nil {
+		return fmt.Errorf("failed to register conversion webhook: %v", err)
+	}
+	server := &http.Server{
+		Addr:      fmt.Sprintf(":%d", config.Port),
+		Handler:   Handler(),
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{serving}},
+	}
+	go func() {
+		logrus.Infof("Serving webhook on %s", server.Addr)
+		if err := server.ListenAndServeTLS("", ""); err != nil {
+			logrus.Fatalf("webhook server failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+func newCertificates(config Config) (tls.Certificate, []byte, error) {
+	caKey, err := cert.NewPrivateKey()
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	caCert, err := cert.NewSelfSignedCACert(cert.Config{CommonName: config.ServiceName + "-ca"}, caKey)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	key, err := cert.NewPrivateKey()
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	host := fmt.Sprintf("%s.%s.svc", config.ServiceName, config.Namespace)
+	servingCert, err := cert.NewSignedCert(cert.Config{
+		CommonName: host,
+		AltNames: cert.AltNames{
+			DNSNames: []string{config.ServiceName, config.ServiceName + "." + config.Namespace, host},
+		},
+		Usages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, key, caCert, caKey)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	serving, err := tls.X509KeyPair(cert.EncodeCertPEM(servingCert), cert.EncodePrivateKeyPEM(key))
+	return serving, cert.EncodeCertPEM(caCert), err
+}
+
+// register creates or updates the ValidatingWebhookConfiguration and the
+// MutatingWebhookConfiguration of the webhook. Requests are admitted when the
+// webhook cannot be reached, since the operator defaults workflows itself and
+// HandlePendingWf rejects invalid ones as well.
+func register(client kubernetes.Interface, config Config, caPEM []byte) error {
+	api := client.AdmissionregistrationV1beta1()
+	validating := newWebhook(config, caPEM, ValidatePath, admissionv1beta1.Create, admissionv1beta1.Update)
+	existing, err := api.ValidatingWebhookConfigurations().Get(config.ConfigurationName, metav1.GetOptions{})
+	switch {
+	case kubeerr.IsNotFound(err):
+		_, err = api.ValidatingWebhookConfigurations().Create(&admissionv1beta1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: config.ConfigurationName},
+			Webhooks:   []admissionv1beta1.Webhook{validating},
+		})
+	case err == nil:
+		existing.Webhooks = []admissionv1beta1.Webhook{validating}
+		_, err = api.ValidatingWebhookConfigurations().Update(existing)
+	}
+	if err != nil {
+		return err
+	}
+
+	mutating := newWebhook(config, caPEM, DefaultPath, admissionv1beta1.Create)
+	existingMutating, err := api.MutatingWebhookConfigurations().Get(config.ConfigurationName, metav1.GetOptions{})
+	switch {
+	case kubeerr.IsNotFound(err):
+		_, err = api.MutatingWebhookConfigurations().Create(&admissionv1beta1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: config.ConfigurationName},
+			Webhooks:   []admissionv1beta1.Webhook{mutating},
+		})
+	case err == nil:
+		existingMutating.Webhooks = []admissionv1beta1.Webhook{mutating}
+		_, err = api.MutatingWebhookConfigurations().Update(existingMutating)
+	}
+	return err
+}
+
+// registerConversion points the Workflow CRD's conversion webhook at the
+// webhook Service with the new CA bundle. CRDs without a conversion webhook,
+// as on clusters older than Kubernetes 1.13, are left alone.
+func registerConversion(config Config, caPEM []byte) error {
+	client, _, err := k8sclient.GetResourceClient("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "")
+	if err != nil {
+		return err
+	}
+	crd, err := client.Get(WorkflowCRD, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy")
+	if strategy != "Webhook" {
+		logrus.Warnf("CRD %s has no conversion webhook, only its storage version can be served", WorkflowCRD)
+		return nil
+	}
+	clientConfig := map[string]interface{}{
+		"caBundle": base64.StdEncoding.EncodeToString(caPEM),
+		"service": map[string]interface{}{
+			"namespace": config.Namespace,
+			"name":      config.ServiceName,
+			"path":      ConvertPath,
+		},
+	}
+	if err := unstructured.SetNestedField(crd.Object, clientConfig, "spec", "conversion", "webhookClientConfig"); err != nil {
+		return err
+	}
+	_, err = client.Update(crd)
+	return err
+}
+
+func newWebhook(config Config, caPEM []byte, path string, operations ...admissionv1beta1.OperationType) admissionv1beta1.Webhook {
+	failurePolicy := admissionv1beta1.Ignore
+	return admissionv1beta1.Webhook{
+		Name: "workflows.threekit.com",
+		ClientConfig: admissionv1beta1.WebhookClientConfig{
+			Service: &admissionv1beta1.ServiceReference{
+				Namespace: config.Namespace,
+				Name:      config.ServiceName,
+				Path:      &path,
+			},
+			CABundle: caPEM,
+		},
+		Rules: []admissionv1beta1.RuleWithOperations{{
+			Operations: operations,
+			Rule: admissionv1beta1.Rule{
+				APIGroups:   []string{"threekit.com"},
+				APIVersions: []string{"v1alpha", "v1beta1"},
+				Resources:   []string{"workflows"},
+			},
+		}},
+		FailurePolicy: &failurePolicy,
+	}
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ConvertPath is the path the API server posts Workflows to convert
+	// between threekit.com/v1alpha and threekit.com/v1beta1.
+	ConvertPath = "/convert"
+	// WorkflowCRD is the name of the CustomResourceDefinition of Workflows.
+	WorkflowCRD = "workflows.threekit.com"
+)
+
+// ConversionReview is the apiextensions.k8s.io/v1beta1 ConversionReview,
+// which is not part of the vendored API either.
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *ConversionRequest  `json:"request,omitempty"`
+	Response        *ConversionResponse `json:"response,omitempty"`
+}
+
+type ConversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+type ConversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+func serveConvert(w http.ResponseWriter, r *http.Request) {
+	review := &ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("invalid conversion review: %v", err), http.StatusBadRequest)
+		return
+	}
+	response := &ConversionResponse{
+		UID:    review.Request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, object := range review.Request.Objects {
+		converted, err := convert(object.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			logrus.Errorf("failed to convert workflow: %v", err)
+			response.ConvertedObjects = nil
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			break
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	review.Request = nil
+	review.Response = response
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		logrus.Errorf("failed to write conversion review: %v", err)
+	}
+}
+
+// convert returns the Workflow in raw at the desired API version.
+func convert(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	var meta metav1.TypeMeta
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, err
+	}
+	if meta.Kind != "Workflow" {
+		return nil, fmt.Errorf("cannot convert kind %s", meta.Kind)
+	}
+	if meta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+	switch {
+	case meta.APIVersion == v1alpha.SchemeGroupVersion.String() && desiredAPIVersion == v1beta1.SchemeGroupVersion.String():
+		in := &v1alpha.Workflow{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		return json.Marshal(v1beta1.ConvertFromV1alpha(in))
+	case meta.APIVersion == v1beta1.SchemeGroupVersion.String() && desiredAPIVersion == v1alpha.SchemeGroupVersion.String():
+		in := &v1beta1.Workflow{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		return json.Marshal(v1beta1.ConvertToV1alpha(in))
+	}
+	return nil, fmt.Errorf("cannot convert from %s to %s", meta.APIVersion, desiredAPIVersion)
+}
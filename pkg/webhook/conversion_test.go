@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestServeConvert(t *testing.T) {
+	alpha := `{"apiVersion":"threekit.com/v1alpha","kind":"Workflow","metadata":{"name":"wf"},"spec":{"jobBatch":{"import":{"kind":"Job","name":"wf-import"}}},"inputs":{"jobs":[{"name":"import","type":"import","data":""}]}}`
+	beta := `{"apiVersion":"threekit.com/v1beta1","kind":"Workflow","metadata":{"name":"wf"},"spec":{"jobs":[{"name":"import","type":"import","data":""}]}}`
+	tests := []struct {
+		name        string
+		objects     []string
+		version     string
+		wantStatus  string
+		wantVersion string
+		wantMessage string
+	}{
+		{name: "to v1beta1", objects: []string{alpha}, version: "threekit.com/v1beta1", wantStatus: metav1.StatusSuccess, wantVersion: "threekit.com/v1beta1"},
+		{name: "to v1alpha", objects: []string{beta}, version: "threekit.com/v1alpha", wantStatus: metav1.StatusSuccess, wantVersion: "threekit.com/v1alpha"},
+		{name: "same version", objects: []string{alpha}, version: "threekit.com/v1alpha", wantStatus: metav1.StatusSuccess, wantVersion: "threekit.com/v1alpha"},
+		{
+			name:        "unknown desired version",
+			objects:     []string{alpha},
+			version:     "threekit.com/v2",
+			wantStatus:  metav1.StatusFailure,
+			wantMessage: "cannot convert from threekit.com/v1alpha to threekit.com/v2",
+		},
+		{
+			name:        "unknown version",
+			objects:     []string{beta, strings.Replace(alpha, "v1alpha", "v0", 1)},
+			version:     "threekit.com/v1alpha",
+			wantStatus:  metav1.StatusFailure,
+			wantMessage: "cannot convert from threekit.com/v0 to threekit.com/v1alpha",
+		},
+		{
+			name:        "unknown kind",
+			objects:     []string{strings.Replace(alpha, `"Workflow"`, `"CronWorkflow"`, 1)},
+			version:     "threekit.com/v1beta1",
+			wantStatus:  metav1.StatusFailure,
+			wantMessage: "cannot convert kind CronWorkflow",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &ConversionRequest{UID: "uid", DesiredAPIVersion: tt.version}
+			for _, object := range tt.objects {
+				request.Objects = append(request.Objects, runtime.RawExtension{Raw: []byte(object)})
+			}
+			body, err := json.Marshal(&ConversionReview{Request: request})
+			if err != nil {
+				t.Fatal(err)
+			}
+			recorder := httptest.NewRecorder()
+			serveConvert(recorder, httptest.NewRequest(http.MethodPost, ConvertPath, bytes.NewReader(body)))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("serveConvert() answered %d: %s", recorder.Code, recorder.Body)
+			}
+			review := &ConversionReview{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), review); err != nil {
+				t.Fatal(err)
+			}
+			response := review.Response
+			if review.Request != nil || response == nil || response.UID != "uid" {
+				t.Fatalf("serveConvert() answered %+v, want the response to request uid", review)
+			}
+			if response.Result.Status != tt.wantStatus || response.Result.Message != tt.wantMessage {
+				t.Errorf("result = %+v, want %s %q", response.Result, tt.wantStatus, tt.wantMessage)
+			}
+			if tt.wantStatus == metav1.StatusFailure {
+				if len(response.ConvertedObjects) != 0 {
+					t.Errorf("serveConvert() failed but converted %d objects", len(response.ConvertedObjects))
+				}
+				return
+			}
+			if len(response.ConvertedObjects) != len(tt.objects) {
+				t.Fatalf("serveConvert() converted %d objects, want %d", len(response.ConvertedObjects), len(tt.objects))
+			}
+			var meta metav1.TypeMeta
+			if err := json.Unmarshal(response.ConvertedObjects[0].Raw, &meta); err != nil {
+				t.Fatal(err)
+			}
+			if meta.APIVersion != tt.wantVersion || meta.Kind != "Workflow" {
+				t.Errorf("converted object is a %s %s, want a %s Workflow", meta.APIVersion, meta.Kind, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestServeConvertInvalid(t *testing.T) {
+	for _, body := range []string{`{"request":`, `{}`} {
+		recorder := httptest.NewRecorder()
+		serveConvert(recorder, httptest.NewRequest(http.MethodPost, ConvertPath, strings.NewReader(body)))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("serveConvert(%s) answered %d, want %d", body, recorder.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package webhook serves the admission and conversion webhooks for Workflows.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1"
+	operator "github.com/Ziyang2go/workflowop/pkg/workflow"
+	"github.com/sirupsen/logrus"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ValidatePath is the path the API server posts Workflow reviews to.
+	ValidatePath = "/validate-workflow"
+	// DefaultPath is the path the API server posts Workflows to default.
+	DefaultPath = "/default-workflow"
+)
+
+// Handler returns the HTTP handler of the webhook.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ValidatePath, serve(validate))
+	mux.HandleFunc(DefaultPath, serve(setDefaults))
+	mux.HandleFunc(ConvertPath, serveConvert)
+	return mux
+}
+
+// serve answers admission reviews with the response of handle.
+func serve(handle func(*AdmissionRequest) *AdmissionResponse) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review := &AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil || review.Request == nil {
+			http.Error(w, fmt.Sprintf("invalid admission review: %v", err), http.StatusBadRequest)
+			return
+		}
+		review.Response = handle(review.Request)
+		review.Response.UID = review.Request.UID
+		review.Request = nil
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(review); err != nil {
+			logrus.Errorf("failed to write admission review: %v", err)
+		}
+	}
+}
+
+// validate admits a Workflow when ValidateWorkflow finds no problem. Updates
+// that leave the jobs and arguments alone are always admitted, so that the
+// operator can still finish workflows created before the webhook existed.
+func validate(req *AdmissionRequest) *AdmissionResponse {
+	if req.Kind.Kind != "Workflow" {
+		return &AdmissionResponse{Allowed: true}
+	}
+	wf, err := decodeWorkflow(req.Object.Raw)
+	if err != nil {
+		return deny(kubeerr.NewBadRequest(fmt.Sprintf("invalid workflow: %v", err)))
+	}
+	if req.Operation == "UPDATE" && len(req.OldObject.Raw) > 0 {
+		if old, err := decodeWorkflow(req.OldObject.Raw); err == nil &&
+			reflect.DeepEqual(old.Inputs, wf.Inputs) && reflect.DeepEqual(old.Spec.Arguments, wf.Spec.Arguments) {
+			return &AdmissionResponse{Allowed: true}
+		}
+	}
+	if wf.Namespace == "" {
+		wf.Namespace = req.Namespace
+	}
+	v1alpha.SetObjectDefaults_Workflow(wf)
+	errs := operator.ValidateWorkflow(wf)
+	if len(errs) == 0 {
+		return &AdmissionResponse{Allowed: true}
+	}
+	name := wf.Name
+	if name == "" {
+		name = wf.GenerateName
+	}
+	logrus.Printf("rejected workflow %s: %v", name, errs.ToAggregate())
+	return deny(kubeerr.NewInvalid(schema.GroupKind{Group: v1alpha.SchemeGroupVersion.Group, Kind: "Workflow"}, name, errs))
+}
+
+// decodeWorkflow returns the Workflow in raw as a v1alpha Workflow, whichever
+// version it was sent in.
+func decodeWorkflow(raw []byte) (*v1alpha.Workflow, error) {
+	converted, err := convert(raw, v1alpha.SchemeGroupVersion.String())
+	if err != nil {
+		return nil, err
+	}
+	wf := &v1alpha.Workflow{}
+	if err := json.Unmarshal(converted, wf); err != nil {
+		return nil, err
+	}
+	return wf, nil
+}
+
+func deny(err *kubeerr.StatusError) *AdmissionResponse {
+	status := err.Status()
+	return &AdmissionResponse{Allowed: false, Result: &status}
+}
+
+// setDefaults patches a new Workflow with its defaults and normalized job
+// names. The status is left alone: the API server drops it on create, and the
+// operator defaults it again.
+func setDefaults(req *AdmissionRequest) *AdmissionResponse {
+	if req.Kind.Kind != "Workflow" {
+		return &AdmissionResponse{Allowed: true}
+	}
+	original, err := decodeWorkflow(req.Object.Raw)
+	if err != nil {
+		return deny(kubeerr.NewBadRequest(fmt.Sprintf("invalid workflow: %v", err)))
+	}
+	wf := original.DeepCopy()
+	if wf.Namespace == "" {
+		wf.Namespace = req.Namespace
+	}
+	v1alpha.SetObjectDefaults_Workflow(wf)
+	v1alpha.NormalizeJobNames(&wf.Inputs)
+
+	var patch []map[string]interface{}
+	add := func(path string, value interface{}) {
+		patch = append(patch, map[string]interface{}{"op": "add", "path": path, "value": value})
+	}
+	if original.Namespace != wf.Namespace {
+		add("/metadata/namespace", wf.Namespace)
+	}
+	if original.Labels == nil {
+		add("/metadata/labels", wf.Labels)
+	}
+	if req.Kind.Version == v1beta1.SchemeGroupVersion.Version {
+		// v1beta1 keeps the inputs under spec.
+		if spec := v1beta1.ConvertFromV1alpha(wf).Spec; !reflect.DeepEqual(v1beta1.ConvertFromV1alpha(original).Spec, spec) {
+			add("/spec", spec)
+		}
+	} else {
+		if !reflect.DeepEqual(original.Inputs, wf.Inputs) {
+			add("/inputs", wf.Inputs)
+		}
+		if !reflect.DeepEqual(original.Spec, wf.Spec) {
+			add("/spec", wf.Spec)
+		}
+	}
+	if len(patch) == 0 {
+		return &AdmissionResponse{Allowed: true}
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return deny(kubeerr.NewInternalError(err))
+	}
+	patchType := PatchTypeJSONPatch
+	return &AdmissionResponse{Allowed: true, Patch: data, PatchType: &patchType}
+}
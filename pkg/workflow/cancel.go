@@ -0,0 +1,108 @@
+package operator
+
+import (
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HandleTerminatingWf deletes the batch Jobs of every running job of a
+// cancelled workflow and marks all unfinished jobs "cancelled". Unfinished
+// Jobs of the workflow in the job cache that its status does not record, such
+// as a Job created just before the status update recording it failed, are
+// deleted too. The workflow stays "terminating" until every deletion
+// succeeded, then becomes "cancelled".
+func (w *WorkflowOp) HandleTerminatingWf(orig *v1alpha.Workflow) error {
+	wf := orig.DeepCopy()
+	migrateJobBatch(wf)
+	now := metav1.NewTime(time.Now())
+	pending := w.deleteUnrecordedJobs(wf)
+	for _, job := range allJobs(wf) {
+		name := job.Name
+		status := wf.Status.JobStatus[name]
+		if status.Finished() {
+			continue
+		}
+		batch := wf.Status.JobBatch[name]
+		if status == v1alpha.JobWorking && batch != nil {
+			err := w.provider.DeleteJob(wf.Namespace, batch.Name)
+			if err != nil && !kubeerr.IsNotFound(err) {
+				logrus.Errorf("failed to delete job %s of cancelled workflow %s: %v", batch.Name, wf.Name, err)
+				pending = true
+				continue
+			}
+			batch.Attempts = append(batch.Attempts, v1alpha.JobAttempt{
+				Name:       batch.Name,
+				Status:     v1alpha.JobCancelled,
+				Reason:     v1alpha.ReasonCancelled,
+				FinishedAt: now,
+			})
+			w.recordFinished(wf, name, batch, v1alpha.JobCancelled, batch.Logs)
+		}
+		if batch != nil {
+			batch.RetryAfter = nil
+			batch.FinishedAt = &now
+		}
+		wf.Status.JobStatus[name] = v1alpha.JobCancelled
+	}
+	if pending {
+		wf.Status.Status = v1alpha.WorkflowTerminating
+		return w.UpdateWorkflow(orig, wf)
+	}
+	logrus.Printf("workflow %s cancelled", wf.Name)
+	wf.Status.Status = v1alpha.WorkflowCancelled
+	SetCondition(&wf.Status, v1alpha.WorkflowCompleted, corev1.ConditionTrue, v1alpha.ReasonCancelled, "workflow was cancelled")
+	return w.UpdateWorkflow(orig, wf)
+}
+
+// deleteUnrecordedJobs deletes the unfinished cached Jobs of wf that are not
+// the current attempt of one of its jobs, and reports whether a deletion
+// failed.
+func (w *WorkflowOp) deleteUnrecordedJobs(wf *v1alpha.Workflow) bool {
+	if w.jobs == nil {
+		return false
+	}
+	jobs, err := w.jobs.ByWorkflow(wf.Namespace, wf.Name)
+	if err != nil {
+		logrus.Errorf("failed to get jobs of cancelled workflow %s from cache: %v", wf.Name, err)
+		return true
+	}
+	recorded := make(map[string]bool)
+	for _, batch := range wf.Status.JobBatch {
+		if batch != nil {
+			recorded[batch.Name] = true
+		}
+	}
+	failed := false
+	for _, job := range jobs {
+		if finished, _ := jobFinished(job); finished || recorded[job.Name] {
+			continue
+		}
+		err := w.provider.DeleteJob(job.Namespace, job.Name)
+		if err != nil && !kubeerr.IsNotFound(err) {
+			logrus.Errorf("failed to delete job %s of cancelled workflow %s: %v", job.Name, wf.Name, err)
+			failed = true
+		}
+	}
+	return failed
+}
+
+// setSuspended records in wf whether spec.suspend currently holds back new
+// jobs and reports whether the condition changed.
+func setSuspended(wf *v1alpha.Workflow) bool {
+	prev := GetCondition(&wf.Status, v1alpha.WorkflowSuspended)
+	suspended := prev != nil && prev.Status == corev1.ConditionTrue
+	if wf.Spec.Suspend == suspended {
+		return false
+	}
+	if wf.Spec.Suspend {
+		SetCondition(&wf.Status, v1alpha.WorkflowSuspended, corev1.ConditionTrue, v1alpha.ReasonSuspended, "no new jobs are created while the workflow is suspended")
+	} else {
+		SetCondition(&wf.Status, v1alpha.WorkflowSuspended, corev1.ConditionFalse, v1alpha.ReasonResumed, "")
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package operator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/kube"
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	template "github.com/Ziyang2go/workflowop/pkg/templates"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// cancelProvider records the Jobs deleted and the workflow status written.
+// Deleting a Job in failDelete fails. The other Provider methods are not
+// implemented.
+type cancelProvider struct {
+	kube.Provider
+	failDelete string
+	deleted    []string
+	updated    *v1alpha.Workflow
+}
+
+func (p *cancelProvider) DeleteJob(namespace, name string) error {
+	if name == p.failDelete {
+		return fmt.Errorf("cannot delete %s", name)
+	}
+	p.deleted = append(p.deleted, namespace+"/"+name)
+	return nil
+}
+
+func (p *cancelProvider) UpdateStatus(object runtime.Object) error {
+	p.updated = object.(*v1alpha.Workflow).DeepCopy()
+	return nil
+}
+
+func TestHandleTerminatingWf(t *testing.T) {
+	wf := &v1alpha.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "wf", Namespace: "ns"},
+		Inputs: v1alpha.WorkflowInputs{Jobs: []v1alpha.Job{
+			{Name: "a", Type: "render"},
+			{Name: "b", Type: "render"},
+			{Name: "c", Type: "render"},
+		}},
+		Status: v1alpha.WorkflowStatus{
+			Status: v1alpha.WorkflowTerminating,
+			JobStatus: map[string]v1alpha.JobPhase{
+				"a": v1alpha.JobWorking,
+				"b": v1alpha.JobPending,
+				"c": v1alpha.JobOK,
+			},
+			JobBatch: map[string]*v1alpha.BatchReference{
+				"a": {Kind: "Job", Name: "wf-a-1"},
+				"c": {Kind: "Job", Name: "wf-c-1"},
+			},
+		},
+	}
+	cachedJob := func(name, workflow string, finished bool) *batchv1.Job {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			Labels:    map[string]string{template.WorkflowLabel: workflow},
+		}}
+		if finished {
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: "True"}}
+		}
+		return job
+	}
+	tests := []struct {
+		name        string
+		failDelete  string
+		wantDeleted []string
+		wantStatus  v1alpha.WorkflowPhase
+	}{
+		{
+			// wf-b-1 was created but the status update recording it failed.
+			name:        "cancelled",
+			wantDeleted: []string{"ns/wf-a-1", "ns/wf-b-1"},
+			wantStatus:  v1alpha.WorkflowCancelled,
+		},
+		{
+			name:        "unrecorded job not deleted",
+			failDelete:  "wf-b-1",
+			wantDeleted: []string{"ns/wf-a-1"},
+			wantStatus:  v1alpha.WorkflowTerminating,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := NewJobCache(nil, "ns", 0)
+			for _, job := range []*batchv1.Job{
+				cachedJob("wf-a-1", "wf", false),
+				cachedJob("wf-b-1", "wf", false),
+				cachedJob("wf-c-1", "wf", true),
+				cachedJob("wf-d-1", "wf", true),
+				cachedJob("other-a-1", "other", false),
+			} {
+				if err := jobs.informer.GetIndexer().Add(job); err != nil {
+					t.Fatal(err)
+				}
+			}
+			p := &cancelProvider{failDelete: tt.failDelete}
+			w := &WorkflowOp{provider: p, jobs: jobs}
+			if err := w.HandleTerminatingWf(wf); err != nil {
+				t.Fatalf("HandleTerminatingWf() failed: %v", err)
+			}
+			sort.Strings(p.deleted)
+			if strings.Join(p.deleted, " ") != strings.Join(tt.wantDeleted, " ") {
+				t.Errorf("deleted %v, want %v", p.deleted, tt.wantDeleted)
+			}
+			if p.updated == nil {
+				t.Fatal("HandleTerminatingWf() did not update the workflow")
+			}
+			if p.updated.Status.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", p.updated.Status.Status, tt.wantStatus)
+			}
+			if status := p.updated.Status.JobStatus["b"]; status != v1alpha.JobCancelled {
+				t.Errorf("status of job b = %s, want cancelled", status)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package operator
+
+import (
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// WorkflowFinalizer holds back the deletion of a workflow until its job
+// history is flushed and its job logs are archived.
+const WorkflowFinalizer = "threekit.com/cleanup"
+
+// Retention is how long finished workflows are kept before they are deleted,
+// unless the workflow sets spec.ttlSecondsAfterFinished. Failed also applies
+// to cancelled workflows.
+type Retention struct {
+	Succeeded time.Duration
+	Failed    time.Duration
+}
+
+// CleanupWf deletes a finished workflow once its retention period is over.
+func (w *WorkflowOp) CleanupWf(workflow *v1alpha.Workflow) error {
+	ttl := w.retention.Failed
+	if workflow.Status.Status == v1alpha.WorkflowOK {
+		ttl = w.retention.Succeeded
+	}
+	if seconds := workflow.Spec.TTLSecondsAfterFinished; seconds != nil {
+		ttl = time.Duration(*seconds) * time.Second
+	}
+	if finished := workflow.Status.FinishedAt; finished != nil && time.Now().Before(finished.Add(ttl)) {
+		return nil
+	}
+	logrus.Printf("deleting finished workflow %s", workflow.Name)
+	err := w.provider.Delete(workflow)
+	if kubeerr.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// HandleDeletingWf archives the logs of jobs whose logs were not archived yet
+// and flushes the final state of every job to the history store, then removes
+// the finalizer so the workflow and its batch Jobs are deleted.
+func (w *WorkflowOp) HandleDeletingWf(orig *v1alpha.Workflow) error {
+	if !hasFinalizer(orig) {
+		return nil
+	}
+	wf := orig.DeepCopy()
+	migrateJobBatch(wf)
+	for name, batch := range wf.Status.JobBatch {
+		if batch == nil || batch.Name == "" {
+			continue
+		}
+		status := wf.Status.JobStatus[name]
+		if w.logSink != nil && batch.LogURL == "" {
+			w.archiveJobLogs(wf, batch)
+		}
+		if w.history != nil {
+			if err := w.history.Update(historyKey(wf, name, currentAttempt(batch)), string(status), batch.Logs); err != nil {
+				logrus.Errorf("failed to flush history of job %s: %v", batch.Name, err)
+			}
+		}
+	}
+	return w.updateFinalizers(orig, func(finalizers []string) []string {
+		var kept []string
+		for _, f := range finalizers {
+			if f != WorkflowFinalizer {
+				kept = append(kept, f)
+			}
+		}
+		return kept
+	})
+}
+
+// archiveJobLogs archives whatever is left of the logs of the current attempt
+// of a job. Pods may already be gone, in which case nothing is archived.
+func (w *WorkflowOp) archiveJobLogs(wf *v1alpha.Workflow, batch *v1alpha.BatchReference) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      batch.Name,
+			Namespace: wf.Namespace,
+		},
+	}
+	if w.jobs != nil {
+		if cached, err := w.jobs.Get(wf.Namespace, batch.Name); err == nil && cached != nil {
+			job = cached
+		}
+	}
+	pod, err := w.GetJobPod(job)
+	if err != nil {
+		logrus.Printf("no logs left to archive for job %s: %v", batch.Name, err)
+		return
+	}
+	_, archive, err := w.GetJobLogs(job, pod)
+	if err != nil {
+		logrus.Errorf("failed to archive logs of job %s: %v", batch.Name, err)
+		return
+	}
+	if archive != nil {
+		logrus.Printf("archived logs of job %s to %s", batch.Name, archive.URL)
+	}
+}
+
+// ensureFinalizer adds WorkflowFinalizer to the workflow. wf is updated with
+// the result from the server.
+func (w *WorkflowOp) ensureFinalizer(wf *v1alpha.Workflow) error {
+	if hasFinalizer(wf) {
+		return nil
+	}
+	return w.updateFinalizers(wf, func(finalizers []string) []string {
+		return append(finalizers, WorkflowFinalizer)
+	})
+}
+
+// updateFinalizers replaces the finalizers of wf by the result of change,
+// retrying on conflicts against the latest version of the workflow.
+func (w *WorkflowOp) updateFinalizers(wf *v1alpha.Workflow, change func([]string) []string) error {
+	return w.updateWorkflowObject(wf, func(wf *v1alpha.Workflow) {
+		wf.SetFinalizers(change(wf.GetFinalizers()))
+	})
+}
+
+// updateWorkflowObject applies change to wf and updates everything but its
+// status, retrying on conflicts against the latest version of the workflow.
+// wf is updated with the result from the server.
+func (w *WorkflowOp) updateWorkflowObject(wf *v1alpha.Workflow, change func(*v1alpha.Workflow)) error {
+	first := true
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			latest, err := w.GetWorkflowByName(wf.Name, wf.Namespace)
+			if err != nil {
+				return err
+			}
+			*wf = *latest
+		}
+		first = false
+		change(wf)
+		return w.provider.Update(wf)
+	})
+}
+
+func hasFinalizer(wf *v1alpha.Workflow) bool {
+	for _, f := range wf.GetFinalizers() {
+		if f == WorkflowFinalizer {
+			return true
+		}
+	}
+	return false
+}
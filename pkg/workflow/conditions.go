@@ -0,0 +1,33 @@
+package operator
+
+import (
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetCondition returns the condition of the given type, or nil.
+func GetCondition(status *v1alpha.WorkflowStatus, condType v1alpha.WorkflowConditionType) *v1alpha.WorkflowCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == condType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates the condition of the given type. The
+// transition time only moves when the condition status changes.
+func SetCondition(status *v1alpha.WorkflowStatus, condType v1alpha.WorkflowConditionType, condStatus corev1.ConditionStatus, reason, message string) {
+	cond := GetCondition(status, condType)
+	if cond == nil {
+		status.Conditions = append(status.Conditions, v1alpha.WorkflowCondition{Type: condType})
+		cond = &status.Conditions[len(status.Conditions)-1]
+	}
+	if cond.Status != condStatus {
+		cond.LastTransitionTime = metav1.Now()
+	}
+	cond.Status = condStatus
+	cond.Reason = reason
+	cond.Message = message
+}
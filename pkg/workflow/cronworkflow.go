@@ -0,0 +1,209 @@
+package operator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/Ziyang2go/workflowop/pkg/cron"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// DefaultSuccessfulHistoryLimit is the number of successful workflows a
+	// CronWorkflow keeps when it does not set a limit.
+	DefaultSuccessfulHistoryLimit = 3
+	// DefaultFailedHistoryLimit is the number of failed workflows a
+	// CronWorkflow keeps when it does not set a limit.
+	DefaultFailedHistoryLimit = 1
+)
+
+// maxMissedSchedules bounds the number of missed runs looked at when the
+// operator was down for a long time. Only the latest one is started.
+const maxMissedSchedules = 100000
+
+// HandleCronWorkflow starts the workflow of a CronWorkflow when it is due and
+// prunes the workflows it created before. It runs on every resync, which
+// makes the resync period the resolution of the schedule.
+func (w *WorkflowOp) HandleCronWorkflow(orig *v1alpha.CronWorkflow) error {
+	if orig.DeletionTimestamp != nil {
+		return nil
+	}
+	cwf := orig.DeepCopy()
+	schedule, err := cron.Parse(cwf.Spec.Schedule)
+	if err != nil {
+		logrus.Errorf("cron workflow %s: %v", cwf.Name, err)
+		return err
+	}
+	loc := time.UTC
+	if cwf.Spec.Timezone != "" {
+		if loc, err = time.LoadLocation(cwf.Spec.Timezone); err != nil {
+			logrus.Errorf("cron workflow %s: invalid timezone %s: %v", cwf.Name, cwf.Spec.Timezone, err)
+			return err
+		}
+	}
+	wl, err := w.provider.ListWorkflows(cwf.Namespace, fmt.Sprintf("%s=%s", v1alpha.CronWorkflowLabel, cwf.Name))
+	if err != nil {
+		logrus.Errorf("failed to list workflows of cron workflow %s: %v", cwf.Name, err)
+		return err
+	}
+	active := w.pruneCronHistory(cwf, wl.Items)
+
+	if !cwf.Spec.Suspend {
+		now := time.Now()
+		scheduled := lastMissedSchedule(cwf, schedule, now.In(loc))
+		switch {
+		case scheduled.IsZero():
+		case cwf.Spec.StartingDeadlineSeconds != nil && exceeded(scheduled, *cwf.Spec.StartingDeadlineSeconds, now):
+			logrus.Printf("cron workflow %s missed its run at %s by more than %ds", cwf.Name, scheduled, *cwf.Spec.StartingDeadlineSeconds)
+			cwf.Status.LastScheduleTime = &metav1.Time{Time: scheduled}
+		case cwf.Spec.ConcurrencyPolicy == v1alpha.ForbidConcurrent && len(active) > 0:
+			logrus.Printf("cron workflow %s skips its run at %s, %d workflows are still running", cwf.Name, scheduled, len(active))
+			// The skipped run is not started late once they finished.
+			cwf.Status.LastScheduleTime = &metav1.Time{Time: scheduled}
+		default:
+			if cwf.Spec.ConcurrencyPolicy == v1alpha.ReplaceConcurrent {
+				for i := range active {
+					if err := w.provider.Delete(&active[i]); err != nil && !kubeerr.IsNotFound(err) {
+						logrus.Errorf("failed to replace workflow %s: %v", active[i].Name, err)
+						return err
+					}
+				}
+				active = nil
+			}
+			wf := cronWorkflowRun(cwf, scheduled)
+			if err := w.provider.Create(wf); err != nil && !kubeerr.IsAlreadyExists(err) {
+				logrus.Errorf("cron workflow %s failed to create workflow %s: %v", cwf.Name, wf.Name, err)
+				return err
+			}
+			logrus.Printf("cron workflow %s created workflow %s", cwf.Name, wf.Name)
+			active = append(active, *wf)
+			cwf.Status.LastScheduleTime = &metav1.Time{Time: scheduled}
+		}
+	}
+
+	cwf.Status.Active = nil
+	for _, wf := range active {
+		cwf.Status.Active = append(cwf.Status.Active, corev1.ObjectReference{
+			Kind:       "Workflow",
+			APIVersion: "threekit.com/v1alpha",
+			Namespace:  wf.Namespace,
+			Name:       wf.Name,
+			UID:        wf.UID,
+		})
+	}
+	if reflect.DeepEqual(orig.Status, cwf.Status) {
+		return nil
+	}
+	if err := w.provider.UpdateStatus(cwf); err != nil {
+		logrus.Errorf("failed to update cron workflow %s: %v", cwf.Name, err)
+		return err
+	}
+	return nil
+}
+
+// pruneCronHistory deletes the oldest finished workflows of cwf beyond its
+// history limits and returns the workflows still running.
+func (w *WorkflowOp) pruneCronHistory(cwf *v1alpha.CronWorkflow, workflows []v1alpha.Workflow) (active []v1alpha.Workflow) {
+	sort.Slice(workflows, func(i, j int) bool {
+		return workflows[i].CreationTimestamp.Before(&workflows[j].CreationTimestamp)
+	})
+	var succeeded, failed []v1alpha.Workflow
+	for _, wf := range workflows {
+		switch {
+		case !wf.Status.Status.Finished():
+			active = append(active, wf)
+		case wf.Status.Status == v1alpha.WorkflowOK:
+			succeeded = append(succeeded, wf)
+		default:
+			failed = append(failed, wf)
+		}
+	}
+	for _, history := range []struct {
+		workflows []v1alpha.Workflow
+		limit     int
+	}{
+		{succeeded, historyLimit(cwf.Spec.SuccessfulHistoryLimit, DefaultSuccessfulHistoryLimit)},
+		{failed, historyLimit(cwf.Spec.FailedHistoryLimit, DefaultFailedHistoryLimit)},
+	} {
+		for i := 0; i < len(history.workflows)-history.limit; i++ {
+			wf := &history.workflows[i]
+			if wf.DeletionTimestamp != nil {
+				continue
+			}
+			if err := w.provider.Delete(wf); err != nil && !kubeerr.IsNotFound(err) {
+				logrus.Errorf("failed to delete workflow %s of cron workflow %s: %v", wf.Name, cwf.Name, err)
+			}
+		}
+	}
+	return active
+}
+
+func historyLimit(limit *int32, fallback int) int {
+	if limit == nil || *limit < 0 {
+		return fallback
+	}
+	return int(*limit)
+}
+
+// lastMissedSchedule returns the latest time schedule was due since the last
+// run of cwf, or since its creation, or the zero time if it was not due.
+func lastMissedSchedule(cwf *v1alpha.CronWorkflow, schedule *cron.Schedule, now time.Time) time.Time {
+	since := cwf.CreationTimestamp.Time
+	if cwf.Status.LastScheduleTime != nil {
+		since = cwf.Status.LastScheduleTime.Time
+	}
+	var last time.Time
+	for t, i := schedule.Next(since.In(now.Location())), 0; !t.IsZero() && !t.After(now); t, i = schedule.Next(t), i+1 {
+		if i == maxMissedSchedules {
+			logrus.Printf("cron workflow %s missed more than %d runs", cwf.Name, maxMissedSchedules)
+			break
+		}
+		last = t
+	}
+	return last
+}
+
+// cronWorkflowRun returns the workflow cwf starts for the run scheduled at t.
+// The name is derived from t so that a run is created only once.
+func cronWorkflowRun(cwf *v1alpha.CronWorkflow, t time.Time) *v1alpha.Workflow {
+	labels := make(map[string]string, len(cwf.Spec.Workflow.Labels)+1)
+	for k, v := range cwf.Spec.Workflow.Labels {
+		labels[k] = v
+	}
+	labels[v1alpha.CronWorkflowLabel] = cwf.Name
+	var annotations map[string]string
+	if len(cwf.Spec.Workflow.Annotations) > 0 {
+		annotations = make(map[string]string, len(cwf.Spec.Workflow.Annotations))
+		for k, v := range cwf.Spec.Workflow.Annotations {
+			annotations[k] = v
+		}
+	}
+	return &v1alpha.Workflow{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Workflow",
+			APIVersion: "threekit.com/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%d", cwf.Name, t.Unix()/60),
+			Namespace:   cwf.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cwf, schema.GroupVersionKind{
+					Group:   v1alpha.SchemeGroupVersion.Group,
+					Version: v1alpha.SchemeGroupVersion.Version,
+					Kind:    "CronWorkflow",
+				}),
+			},
+		},
+		Inputs: *cwf.Spec.Workflow.Inputs.DeepCopy(),
+		Spec:   *cwf.Spec.Workflow.Spec.DeepCopy(),
+	}
+}
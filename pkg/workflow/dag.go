@@ -0,0 +1,200 @@
+package operator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+)
+
+// SortJobs returns the workflow jobs in dependency order, so that every job
+// comes after all of the jobs it depends on. It fails if a job name is
+// duplicated or taken by the children of a fan-out job, a dependency does not
+// exist, a fan-out job is invalid, or the dependencies form a cycle.
+func SortJobs(jobs []v1alpha.Job) ([]v1alpha.Job, error) {
+	byName := make(map[string]v1alpha.Job, len(jobs))
+	for _, job := range jobs {
+		if _, found := byName[job.Name]; found {
+			return nil, fmt.Errorf("duplicate job name %s", job.Name)
+		}
+		byName[job.Name] = job
+	}
+	for _, job := range jobs {
+		for _, dep := range job.DependsOn {
+			if _, found := byName[dep]; !found {
+				return nil, fmt.Errorf("job %s depends on unknown job %s", job.Name, dep)
+			}
+		}
+		if err := validateFanOut(job, jobs); err != nil {
+			return nil, err
+		}
+		for _, ref := range outputReferences(job.Data) {
+			if !contains(job.DependsOn, ref) {
+				return nil, fmt.Errorf("job %s refers to outputs of %s which it does not depend on", job.Name, ref)
+			}
+		}
+		if job.When != "" {
+			expr, err := parseWhen(job.When)
+			if err != nil {
+				return nil, fmt.Errorf("job %s has an invalid when expression: %v", job.Name, err)
+			}
+			for _, ref := range whenJobReferences(expr) {
+				if !contains(job.DependsOn, ref) {
+					return nil, fmt.Errorf("job %s has a when expression on %s which it does not depend on", job.Name, ref)
+				}
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(jobs))
+	sorted := make([]v1alpha.Job, 0, len(jobs))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					cycle := append(append([]string{}, path[i:]...), name)
+					return fmt.Errorf("dependency cycle %s", strings.Join(cycle, " -> "))
+				}
+			}
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range byName[name].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		sorted = append(sorted, byName[name])
+		return nil
+	}
+	for _, job := range jobs {
+		if err := visit(job.Name); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// validateFanOut checks the fan-out fields of job against the other jobs of
+// the workflow.
+func validateFanOut(job v1alpha.Job, jobs []v1alpha.Job) error {
+	if !job.FanOut() {
+		if job.Parallelism != nil {
+			return fmt.Errorf("job %s sets parallelism without withItems or withParam", job.Name)
+		}
+		return nil
+	}
+	if len(job.WithItems) > 0 && job.WithParam != "" {
+		return fmt.Errorf("job %s sets both withItems and withParam", job.Name)
+	}
+	if job.Parallelism != nil && *job.Parallelism < 1 {
+		return fmt.Errorf("job %s has parallelism %d, it must be at least 1", job.Name, *job.Parallelism)
+	}
+	if job.WithParam != "" && !contains(job.DependsOn, job.WithParam) {
+		return fmt.Errorf("job %s takes withParam from %s which it does not depend on", job.Name, job.WithParam)
+	}
+	for _, other := range jobs {
+		if suffix := strings.TrimPrefix(other.Name, job.Name+"-"); suffix != other.Name {
+			if _, err := strconv.Atoi(suffix); err == nil {
+				return fmt.Errorf("job name %s is taken by a child of fan-out job %s", other.Name, job.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateAttemptNames checks that no job is named like a retry of another
+// job, or of a child of a fan-out job: both would get the same batch Job.
+// strategy is the workflow retry strategy, which applies to the jobs without
+// their own.
+func validateAttemptNames(jobs []v1alpha.Job, strategy *v1alpha.RetryStrategy) error {
+	for _, job := range jobs {
+		limit := 0
+		if job.RetryStrategy != nil {
+			limit = int(job.RetryStrategy.Limit)
+		} else if strategy != nil {
+			limit = int(strategy.Limit)
+		}
+		if limit < 1 {
+			continue
+		}
+		for _, other := range jobs {
+			suffix := strings.TrimPrefix(other.Name, job.Name+"-")
+			if suffix == other.Name {
+				continue
+			}
+			parts := strings.Split(suffix, "-")
+			if len(parts) == 2 && job.FanOut() {
+				if _, ok := nameIndex(parts[0]); !ok {
+					continue
+				}
+			} else if len(parts) != 1 {
+				continue
+			}
+			if retry, ok := nameIndex(parts[len(parts)-1]); ok && retry >= 1 && retry <= limit {
+				return fmt.Errorf("job name %s is taken by a retry of job %s", other.Name, job.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// nameIndex parses the index of a fan-out child or an attempt as it appears
+// in batch Job names.
+func nameIndex(s string) (int, bool) {
+	i, err := strconv.Atoi(s)
+	return i, err == nil && strconv.Itoa(i) == s
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// upstreamState summarises the statuses of the jobs a job depends on. ready
+// is true once every upstream job is "ok" or was skipped by its when
+// expression; blocked is true as soon as one of them failed, timed out, was
+// cancelled or skipped otherwise, in which case the job can never run. A job
+// with a when expression is ready once every upstream job finished and never
+// blocked: the expression decides.
+func upstreamState(job v1alpha.Job, status *v1alpha.WorkflowStatus) (ready, blocked bool) {
+	ready = true
+	for _, dep := range job.DependsOn {
+		phase := status.JobStatus[dep]
+		switch {
+		case job.When != "":
+			ready = ready && phase.Finished()
+		case phase == v1alpha.JobOK:
+		case phase == v1alpha.JobSkipped && skippedByCondition(dep, status):
+		case phase.Finished():
+			return false, true
+		default:
+			ready = false
+		}
+	}
+	return ready, false
+}
+
+// skippedByCondition reports whether the named job was skipped by its when
+// expression.
+func skippedByCondition(name string, status *v1alpha.WorkflowStatus) bool {
+	batch := status.JobBatch[name]
+	return batch != nil && batch.Reason == v1alpha.ReasonConditionFalse
+}
@@ -0,0 +1,209 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func dagJob(name string, dependsOn ...string) v1alpha.Job {
+	return v1alpha.Job{Name: name, Type: "render", DependsOn: dependsOn}
+}
+
+func TestSortJobs(t *testing.T) {
+	one := int32(1)
+	zero := int32(0)
+	items := []runtime.RawExtension{{Raw: []byte(`1`)}, {Raw: []byte(`2`)}}
+	tests := []struct {
+		name    string
+		jobs    []v1alpha.Job
+		want    []string
+		wantErr string
+	}{
+		{name: "empty", want: []string{}},
+		{name: "independent jobs keep their order", jobs: []v1alpha.Job{dagJob("b"), dagJob("a")}, want: []string{"b", "a"}},
+		{name: "chain", jobs: []v1alpha.Job{dagJob("c", "b"), dagJob("b", "a"), dagJob("a")}, want: []string{"a", "b", "c"}},
+		{
+			name: "diamond",
+			jobs: []v1alpha.Job{dagJob("d", "b", "c"), dagJob("b", "a"), dagJob("c", "a"), dagJob("a")},
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "references to dependencies",
+			jobs: []v1alpha.Job{
+				{Name: "export", Type: "export", DependsOn: []string{"import"}, Data: `{"id":"{{jobs.import.outputs.assetId}}"}`, When: `jobs.import.status == "ok"`},
+				dagJob("import"),
+			},
+			want: []string{"import", "export"},
+		},
+		{name: "duplicate", jobs: []v1alpha.Job{dagJob("a"), dagJob("a")}, wantErr: "duplicate job name a"},
+		{name: "unknown dependency", jobs: []v1alpha.Job{dagJob("a", "b")}, wantErr: "job a depends on unknown job b"},
+		{name: "self cycle", jobs: []v1alpha.Job{dagJob("a", "a")}, wantErr: "dependency cycle a -> a"},
+		{
+			name:    "cycle",
+			jobs:    []v1alpha.Job{dagJob("a", "b"), dagJob("b", "c"), dagJob("c", "a")},
+			wantErr: "dependency cycle a -> b -> c -> a",
+		},
+		{
+			name:    "cycle below a job",
+			jobs:    []v1alpha.Job{dagJob("x", "a"), dagJob("a", "b"), dagJob("b", "a")},
+			wantErr: "dependency cycle a -> b -> a",
+		},
+		{
+			name:    "outputs of a job not depended on",
+			jobs:    []v1alpha.Job{{Name: "export", Type: "export", Data: `{"id":"{{jobs.import.outputs.assetId}}"}`}, dagJob("import")},
+			wantErr: "job export refers to outputs of import which it does not depend on",
+		},
+		{
+			name:    "when on a job not depended on",
+			jobs:    []v1alpha.Job{{Name: "export", Type: "export", When: `jobs.import.status == "ok"`}, dagJob("import")},
+			wantErr: "job export has a when expression on import which it does not depend on",
+		},
+		{name: "invalid when", jobs: []v1alpha.Job{{Name: "a", Type: "render", When: `jobs.b.status ==`}}, wantErr: "job a has an invalid when expression"},
+		{
+			name:    "parallelism without fan-out",
+			jobs:    []v1alpha.Job{{Name: "a", Type: "render", Parallelism: &one}},
+			wantErr: "job a sets parallelism without withItems or withParam",
+		},
+		{
+			name:    "zero parallelism",
+			jobs:    []v1alpha.Job{{Name: "a", Type: "render", WithItems: items, Parallelism: &zero}},
+			wantErr: "job a has parallelism 0, it must be at least 1",
+		},
+		{
+			name:    "withItems and withParam",
+			jobs:    []v1alpha.Job{{Name: "a", Type: "render", WithItems: items, WithParam: "b", DependsOn: []string{"b"}}, dagJob("b")},
+			wantErr: "job a sets both withItems and withParam",
+		},
+		{
+			name:    "withParam not depended on",
+			jobs:    []v1alpha.Job{{Name: "a", Type: "render", WithParam: "b"}, dagJob("b")},
+			wantErr: "job a takes withParam from b which it does not depend on",
+		},
+		{
+			name:    "name of a fan-out child",
+			jobs:    []v1alpha.Job{{Name: "a", Type: "render", WithItems: items}, dagJob("a-1")},
+			wantErr: "job name a-1 is taken by a child of fan-out job a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := SortJobs(tt.jobs)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SortJobs() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SortJobs() failed: %v", err)
+			}
+			got := make([]string, 0, len(sorted))
+			for _, job := range sorted {
+				got = append(got, job.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("SortJobs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpstreamState(t *testing.T) {
+	status := &v1alpha.WorkflowStatus{
+		JobStatus: map[string]v1alpha.JobPhase{
+			"ok":         v1alpha.JobOK,
+			"working":    v1alpha.JobWorking,
+			"failed":     v1alpha.JobFailed,
+			"condition":  v1alpha.JobSkipped,
+			"blocked":    v1alpha.JobSkipped,
+			"cancelled":  v1alpha.JobCancelled,
+			"retrying":   v1alpha.JobRetrying,
+			"timedOut":   v1alpha.JobTimedOut,
+			"notStarted": v1alpha.JobPending,
+			"queued":     v1alpha.JobQueued,
+		},
+		JobBatch: map[string]*v1alpha.BatchReference{
+			"condition": {Reason: v1alpha.ReasonConditionFalse},
+		},
+	}
+	tests := []struct {
+		name        string
+		job         v1alpha.Job
+		wantReady   bool
+		wantBlocked bool
+	}{
+		{name: "no dependencies", job: dagJob("a"), wantReady: true},
+		{name: "ok", job: dagJob("a", "ok"), wantReady: true},
+		{name: "skipped by condition", job: dagJob("a", "ok", "condition"), wantReady: true},
+		{name: "running", job: dagJob("a", "ok", "working"), wantReady: false},
+		{name: "retrying", job: dagJob("a", "retrying"), wantReady: false},
+		{name: "queued", job: dagJob("a", "queued"), wantReady: false},
+		{name: "pending", job: dagJob("a", "notStarted"), wantReady: false},
+		{name: "failed", job: dagJob("a", "working", "failed"), wantBlocked: true},
+		{name: "skipped as blocked", job: dagJob("a", "blocked"), wantBlocked: true},
+		{name: "cancelled", job: dagJob("a", "cancelled"), wantBlocked: true},
+		{name: "timed out", job: dagJob("a", "timedOut"), wantBlocked: true},
+		{name: "when on failed", job: v1alpha.Job{Name: "a", DependsOn: []string{"ok", "failed"}, When: "true"}, wantReady: true},
+		{name: "when on running", job: v1alpha.Job{Name: "a", DependsOn: []string{"failed", "working"}, When: "true"}, wantReady: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, blocked := upstreamState(tt.job, status)
+			if ready != tt.wantReady || blocked != tt.wantBlocked {
+				t.Errorf("upstreamState() = %v, %v, want %v, %v", ready, blocked, tt.wantReady, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestValidateAttemptNames(t *testing.T) {
+	items := []runtime.RawExtension{{Raw: []byte(`1`)}, {Raw: []byte(`2`)}}
+	twice := &v1alpha.RetryStrategy{Limit: 2}
+	never := &v1alpha.RetryStrategy{Limit: 0}
+	tests := []struct {
+		name     string
+		jobs     []v1alpha.Job
+		strategy *v1alpha.RetryStrategy
+		wantErr  string
+	}{
+		{name: "no retries", jobs: []v1alpha.Job{dagJob("a"), dagJob("a-1")}},
+		{name: "retry of a job", jobs: []v1alpha.Job{dagJob("a"), dagJob("a-1")}, strategy: twice, wantErr: "job name a-1 is taken by a retry of job a"},
+		{name: "last retry", jobs: []v1alpha.Job{dagJob("a-2"), dagJob("a")}, strategy: twice, wantErr: "job name a-2 is taken by a retry of job a"},
+		{name: "beyond the last retry", jobs: []v1alpha.Job{dagJob("a"), dagJob("a-3")}, strategy: twice},
+		{name: "not an index", jobs: []v1alpha.Job{dagJob("a"), dagJob("a-01"), dagJob("a-b")}, strategy: twice},
+		{
+			name:    "job strategy",
+			jobs:    []v1alpha.Job{{Name: "a", Type: "render", RetryStrategy: twice}, dagJob("a-1")},
+			wantErr: "job name a-1 is taken by a retry of job a",
+		},
+		{
+			name:     "job without retries",
+			jobs:     []v1alpha.Job{{Name: "a", Type: "render", RetryStrategy: never}, dagJob("a-1")},
+			strategy: twice,
+		},
+		{
+			name:     "retry of a fan-out child",
+			jobs:     []v1alpha.Job{{Name: "render", Type: "render", WithItems: items}, dagJob("render-1-1")},
+			strategy: twice,
+			wantErr:  "job name render-1-1 is taken by a retry of job render",
+		},
+		{name: "two indexes without fan-out", jobs: []v1alpha.Job{dagJob("a"), dagJob("a-1-1")}, strategy: twice},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAttemptNames(tt.jobs, tt.strategy)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateAttemptNames() failed: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validateAttemptNames() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
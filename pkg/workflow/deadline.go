@@ -0,0 +1,79 @@
+package operator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/sirupsen/logrus"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnforceDeadlines deletes the batch Jobs of jobs that ran past their active
+// deadline and marks them "timedOut". When the workflow itself is past its
+// deadline every running job is stopped, jobs that did not start are skipped
+// and a non-empty failure message is returned. wf is updated in place; changed
+// reports whether anything was modified.
+func (w *WorkflowOp) EnforceDeadlines(jobs []v1alpha.Job, wf *v1alpha.Workflow, now time.Time) (changed bool, message string) {
+	if deadline := wf.Inputs.ActiveDeadlineSeconds; deadline != nil {
+		started := wf.CreationTimestamp.Time
+		if wf.Status.StartedAt != nil {
+			started = wf.Status.StartedAt.Time
+		}
+		if exceeded(started, *deadline, now) {
+			message = fmt.Sprintf("workflow exceeded its active deadline of %ds", *deadline)
+		}
+	}
+	for _, job := range jobs {
+		name := job.Name
+		batch := wf.Status.JobBatch[name]
+		switch status := wf.Status.JobStatus[name]; status {
+		case v1alpha.JobWorking:
+			timedOut := message != ""
+			if deadline := job.ActiveDeadlineSeconds; deadline != nil && batch.StartedAt != nil {
+				timedOut = timedOut || exceeded(batch.StartedAt.Time, *deadline, now)
+			}
+			if !timedOut {
+				continue
+			}
+			logrus.Printf("job %s exceeded its active deadline, deleting %s", name, batch.Name)
+			if err := w.provider.DeleteJob(wf.Namespace, batch.Name); err != nil && !kubeerr.IsNotFound(err) {
+				logrus.Errorf("failed to delete timed out job %s: %v", batch.Name, err)
+				continue
+			}
+			finished := metav1.NewTime(now)
+			batch.Attempts = append(batch.Attempts, v1alpha.JobAttempt{
+				Name:       batch.Name,
+				Status:     v1alpha.JobTimedOut,
+				Reason:     v1alpha.FailureReasonDeadlineExceeded,
+				FinishedAt: finished,
+			})
+			batch.FinishedAt = &finished
+			wf.Status.JobStatus[name] = v1alpha.JobTimedOut
+			w.recordFinished(wf, name, batch, v1alpha.JobTimedOut, batch.Logs)
+			changed = true
+		case v1alpha.JobRetrying:
+			if message != "" {
+				finished := metav1.NewTime(now)
+				batch.RetryAfter = nil
+				batch.FinishedAt = &finished
+				wf.Status.JobStatus[name] = v1alpha.JobTimedOut
+				changed = true
+			}
+		case "", v1alpha.JobPending, v1alpha.JobQueued:
+			if message != "" {
+				if batch != nil {
+					batch.RetryAfter = nil
+				}
+				wf.Status.JobStatus[name] = v1alpha.JobSkipped
+				changed = true
+			}
+		}
+	}
+	return changed, message
+}
+
+func exceeded(started time.Time, deadlineSeconds int64, now time.Time) bool {
+	return now.Sub(started) > time.Duration(deadlineSeconds)*time.Second
+}
@@ -0,0 +1,300 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+)
+
+var itemPattern = regexp.MustCompile(`\{\{\s*item((?:\.[A-Za-z0-9_-]+)*)\s*\}\}`)
+
+// childName is the name of the index-th child job of a fan-out job.
+func childName(parent string, index int) string {
+	return parent + "-" + strconv.Itoa(index)
+}
+
+// fanOutParent returns the fan-out job of wf that the named job is a child of.
+func fanOutParent(name string, wf *v1alpha.Workflow) (v1alpha.Job, bool) {
+	for _, job := range WorkflowJobs(wf) {
+		if !job.FanOut() || !strings.HasPrefix(name, job.Name+"-") {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimPrefix(name, job.Name+"-")); err == nil {
+			return job, true
+		}
+	}
+	return v1alpha.Job{}, false
+}
+
+// fanOutItems returns the items of a fan-out job. known is false while the
+// job its items come from has not succeeded yet.
+func fanOutItems(job v1alpha.Job, wf *v1alpha.Workflow) (items []json.RawMessage, known bool, err error) {
+	if job.WithParam == "" {
+		for _, item := range job.WithItems {
+			items = append(items, json.RawMessage(item.Raw))
+		}
+		return items, true, nil
+	}
+	if wf.Status.JobStatus[job.WithParam] != v1alpha.JobOK {
+		return nil, false, nil
+	}
+	var result string
+	if batch := wf.Status.JobBatch[job.WithParam]; batch != nil {
+		result = batch.Result
+	}
+	if err := json.Unmarshal([]byte(result), &items); err != nil {
+		return nil, true, fmt.Errorf("result of job %s is not a JSON array: %v", job.WithParam, err)
+	}
+	return items, true, nil
+}
+
+// fanOutChildren returns the child jobs of a fan-out job, with {{item}}
+// references in their data replaced.
+func fanOutChildren(job v1alpha.Job, wf *v1alpha.Workflow) (children []v1alpha.Job, known bool, err error) {
+	items, known, err := fanOutItems(job, wf)
+	if !known || err != nil {
+		return nil, known, err
+	}
+	for i, item := range items {
+		data, err := substituteItem(job.Data, item)
+		if err != nil {
+			return nil, true, fmt.Errorf("item %d: %v", i, err)
+		}
+		children = append(children, v1alpha.Job{
+			Name:                  childName(job.Name, i),
+			Type:                  job.Type,
+			Data:                  data,
+			DependsOn:             job.DependsOn,
+			RetryStrategy:         job.RetryStrategy,
+			ActiveDeadlineSeconds: job.ActiveDeadlineSeconds,
+			When:                  job.When,
+		})
+	}
+	return children, true, nil
+}
+
+// substituteItem replaces {{item}} and {{item.field.field}} in data. String
+// values are JSON escaped, to be quoted in data like "{{item}}", other values
+// are inserted as JSON.
+func substituteItem(data string, item json.RawMessage) (string, error) {
+	var err error
+	result := itemPattern.ReplaceAllStringFunc(data, func(ref string) string {
+		value := item
+		path := itemPattern.FindStringSubmatch(ref)[1]
+		for _, field := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+			if field == "" {
+				continue
+			}
+			var object map[string]json.RawMessage
+			if e := json.Unmarshal(value, &object); e != nil {
+				err = fmt.Errorf("%s: item is not an object", ref)
+				return ref
+			}
+			next, found := object[field]
+			if !found {
+				err = fmt.Errorf("%s: no such field", ref)
+				return ref
+			}
+			value = next
+		}
+		var s string
+		if json.Unmarshal(value, &s) == nil {
+			return jsonEscape(s)
+		}
+		return string(value)
+	})
+	return result, err
+}
+
+// jsonEscape escapes s for use inside a JSON string.
+func jsonEscape(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted[1 : len(quoted)-1])
+}
+
+// expandJobs replaces the fan-out jobs of jobs, in dependency order, by their
+// child jobs. A fan-out job whose items are not known yet is kept so that it
+// can be skipped or time out like any other job. A fan-out job whose items
+// cannot be expanded is marked "failed". wf is updated in place; changed
+// reports whether anything was modified.
+func expandJobs(jobs []v1alpha.Job, wf *v1alpha.Workflow) (expanded []v1alpha.Job, changed bool) {
+	for _, job := range jobs {
+		if !job.FanOut() {
+			expanded = append(expanded, job)
+			continue
+		}
+		children, known, err := fanOutChildren(job, wf)
+		if err != nil {
+			if wf.Status.JobStatus[job.Name] != v1alpha.JobFailed {
+				setFanOutStatus(wf, job.Name, v1alpha.FanOutStatus{Message: err.Error()})
+				wf.Status.JobStatus[job.Name] = v1alpha.JobFailed
+				changed = true
+			}
+			continue
+		}
+		if !known {
+			expanded = append(expanded, job)
+			continue
+		}
+		expanded = append(expanded, children...)
+	}
+	return expanded, changed
+}
+
+// allJobs returns the jobs of wf followed by the known child jobs of its
+// fan-out jobs.
+func allJobs(wf *v1alpha.Workflow) []v1alpha.Job {
+	parents := WorkflowJobs(wf)
+	jobs := append([]v1alpha.Job{}, parents...)
+	for _, job := range parents {
+		if job.FanOut() {
+			children, _, _ := fanOutChildren(job, wf)
+			jobs = append(jobs, children...)
+		}
+	}
+	return jobs
+}
+
+// fanOutRunning counts the running children of every fan-out job with a
+// Parallelism.
+type fanOutRunning struct {
+	limits  map[string]int32
+	running map[string]int32
+}
+
+func newFanOutRunning(wf *v1alpha.Workflow) *fanOutRunning {
+	r := &fanOutRunning{limits: make(map[string]int32), running: make(map[string]int32)}
+	for _, job := range WorkflowJobs(wf) {
+		if job.FanOut() && job.Parallelism != nil {
+			r.limits[job.Name] = *job.Parallelism
+		}
+	}
+	if len(r.limits) == 0 {
+		return r
+	}
+	for name, status := range wf.Status.JobStatus {
+		if parent, found := r.parent(name); found && childRunning(status) {
+			r.running[parent]++
+		}
+	}
+	return r
+}
+
+// parent returns the fan-out job with a Parallelism that the named job is a
+// child of.
+func (r *fanOutRunning) parent(name string) (string, bool) {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return "", false
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return "", false
+	}
+	_, found := r.limits[name[:i]]
+	return name[:i], found
+}
+
+// reached reports whether the fan-out job the named job is a child of
+// already runs as many children as its Parallelism allows.
+func (r *fanOutRunning) reached(name string) bool {
+	parent, found := r.parent(name)
+	return found && r.running[parent] >= r.limits[parent]
+}
+
+// set records that the named job moved from phase from to phase to.
+func (r *fanOutRunning) set(name string, from, to v1alpha.JobPhase) {
+	parent, found := r.parent(name)
+	if !found {
+		return
+	}
+	if childRunning(from) {
+		r.running[parent]--
+	}
+	if childRunning(to) {
+		r.running[parent]++
+	}
+}
+
+// childRunning reports whether a child job in phase counts against the
+// Parallelism of its fan-out job.
+func childRunning(phase v1alpha.JobPhase) bool {
+	switch phase {
+	case v1alpha.JobWorking, v1alpha.JobRetrying, v1alpha.JobQueued:
+		return true
+	}
+	return false
+}
+
+// aggregateFanOut derives the phase and counts of every expanded fan-out job
+// from its children. A fan-out job succeeds once every child succeeded, and
+// fails once every child finished and one of them did not succeed. wf is
+// updated in place; the result reports whether anything changed.
+func aggregateFanOut(jobs []v1alpha.Job, wf *v1alpha.Workflow) bool {
+	changed := false
+	for _, job := range jobs {
+		if !job.FanOut() || wf.Status.JobStatus[job.Name].Finished() {
+			continue
+		}
+		children, known, err := fanOutChildren(job, wf)
+		if !known || err != nil {
+			continue
+		}
+		fanOut := v1alpha.FanOutStatus{Total: int32(len(children))}
+		finished, skipped, cancelled := 0, 0, 0
+		started := false
+		for _, child := range children {
+			status := wf.Status.JobStatus[child.Name]
+			switch status {
+			case v1alpha.JobOK:
+				fanOut.Succeeded++
+			case v1alpha.JobFailed, v1alpha.JobTimedOut:
+				fanOut.Failed++
+			case v1alpha.JobSkipped:
+				skipped++
+			case v1alpha.JobCancelled:
+				cancelled++
+			case v1alpha.JobWorking, v1alpha.JobRetrying, v1alpha.JobQueued:
+				fanOut.Running++
+			}
+			if status.Finished() {
+				finished++
+			}
+			if status != "" && status != v1alpha.JobPending {
+				started = true
+			}
+		}
+		phase := v1alpha.JobPending
+		switch {
+		case finished == len(children) && skipped == len(children) && skipped > 0:
+			phase = v1alpha.JobSkipped
+		case finished == len(children) && fanOut.Failed > 0:
+			phase = v1alpha.JobFailed
+		case finished == len(children) && cancelled > 0:
+			phase = v1alpha.JobCancelled
+		case finished == len(children):
+			phase = v1alpha.JobOK
+		case started:
+			phase = v1alpha.JobWorking
+		}
+		if setFanOutStatus(wf, job.Name, fanOut) {
+			changed = true
+		}
+		if wf.Status.JobStatus[job.Name] != phase {
+			wf.Status.JobStatus[job.Name] = phase
+			changed = true
+		}
+	}
+	return changed
+}
+
+func setFanOutStatus(wf *v1alpha.Workflow, name string, fanOut v1alpha.FanOutStatus) bool {
+	if prev, found := wf.Status.FanOut[name]; found && prev == fanOut {
+		return false
+	}
+	wf.Status.FanOut[name] = fanOut
+	return true
+}
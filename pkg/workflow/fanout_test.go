@@ -0,0 +1,85 @@
+package operator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestSubstituteItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		item    string
+		want    string
+		wantErr bool
+	}{
+		{name: "string", data: `{"sceneId":"{{item}}"}`, item: `"scene-1"`, want: `{"sceneId":"scene-1"}`},
+		{name: "escaped string", data: `{"title":"{{item}}"}`, item: `"say \"hi\"\\\n"`, want: `{"title":"say \"hi\"\\\n"}`},
+		{name: "object", data: `{"configuration":{{item}}}`, item: `{"Leg":"choice_083"}`, want: `{"configuration":{"Leg":"choice_083"}}`},
+		{name: "number", data: `{"size":{{ item }}}`, item: `3`, want: `{"size":3}`},
+		{name: "field", data: `{"leg":"{{item.Leg}}"}`, item: `{"Leg":"choice \"083\""}`, want: `{"leg":"choice \"083\""}`},
+		{name: "nested field", data: `{{item.a.b}}`, item: `{"a":{"b":[1,2]}}`, want: `[1,2]`},
+		{name: "missing field", data: `{{item.Fabric}}`, item: `{"Leg":"choice_083"}`, wantErr: true},
+		{name: "field of a string", data: `{{item.Leg}}`, item: `"choice_083"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := substituteItem(tt.data, json.RawMessage(tt.item))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("substituteItem() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("substituteItem() = %s, want %s", got, tt.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("substituteItem() = %s, which is not valid JSON", got)
+			}
+		})
+	}
+}
+
+func TestFanOutRunning(t *testing.T) {
+	two := int32(2)
+	wf := &v1alpha.Workflow{
+		Inputs: v1alpha.WorkflowInputs{Jobs: []v1alpha.Job{
+			{Name: "render", Type: "render", Parallelism: &two, WithItems: []runtime.RawExtension{{Raw: []byte(`1`)}, {Raw: []byte(`2`)}, {Raw: []byte(`3`)}}},
+			{Name: "export", Type: "export", WithItems: []runtime.RawExtension{{Raw: []byte(`1`)}}},
+			{Name: "render-all", Type: "render"},
+		}},
+		Status: v1alpha.WorkflowStatus{JobStatus: map[string]v1alpha.JobPhase{
+			"render-0":   v1alpha.JobWorking,
+			"render-1":   v1alpha.JobOK,
+			"export-0":   v1alpha.JobWorking,
+			"render-all": v1alpha.JobWorking,
+		}},
+	}
+	running := newFanOutRunning(wf)
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"render-2", false},
+		{"export-0", false},
+		{"render-all", false},
+		{"render", false},
+	}
+	for _, tt := range tests {
+		if got := running.reached(tt.name); got != tt.want {
+			t.Errorf("reached(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	running.set("render-2", v1alpha.JobPending, v1alpha.JobQueued)
+	if !running.reached("render-2") {
+		t.Error("reached(render-2) = false once two children run, want true")
+	}
+	running.set("render-0", v1alpha.JobWorking, v1alpha.JobOK)
+	if running.reached("render-2") {
+		t.Error("reached(render-2) = true once a child finished, want false")
+	}
+}
@@ -0,0 +1,54 @@
+package operator
+
+import (
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/Ziyang2go/workflowop/pkg/mongo"
+	"github.com/sirupsen/logrus"
+)
+
+// HistoryStore keeps a record of every job that outlives the Workflow and its
+// batch Jobs. It is satisfied by mongo.MongoSVC.
+type HistoryStore interface {
+	Create(key mongo.JobKey, name, status, org, jobType string) error
+	Update(key mongo.JobKey, status, jobLog string) error
+	Finish(key mongo.JobKey, status, jobLog string) error
+}
+
+// historyKey identifies attempt of job name of wf in the history store.
+func historyKey(wf *v1alpha.Workflow, name string, attempt int) mongo.JobKey {
+	return mongo.JobKey{
+		Namespace:   wf.Namespace,
+		Workflow:    wf.Name,
+		WorkflowUID: string(wf.UID),
+		Job:         name,
+		Attempt:     attempt,
+	}
+}
+
+// currentAttempt returns the index of the attempt batch.Name runs, whether
+// or not it was recorded in batch.Attempts yet.
+func currentAttempt(batch *v1alpha.BatchReference) int {
+	if n := len(batch.Attempts); n > 0 && batch.Attempts[n-1].Name == batch.Name {
+		return n - 1
+	}
+	return len(batch.Attempts)
+}
+
+func (w *WorkflowOp) recordCreated(wf *v1alpha.Workflow, name string, attempt int, batchName string, status v1alpha.JobPhase, job v1alpha.Job) {
+	if w.history == nil {
+		return
+	}
+	org := wf.GetLabels()[v1alpha.OrganizationLabel]
+	if err := w.history.Create(historyKey(wf, name, attempt), batchName, string(status), org, job.Type); err != nil {
+		logrus.Errorf("failed to record job %s in history: %v", batchName, err)
+	}
+}
+
+func (w *WorkflowOp) recordFinished(wf *v1alpha.Workflow, name string, batch *v1alpha.BatchReference, status v1alpha.JobPhase, jobLog string) {
+	if w.history == nil {
+		return
+	}
+	if err := w.history.Finish(historyKey(wf, name, currentAttempt(batch)), string(status), jobLog); err != nil {
+		logrus.Errorf("failed to record completion of job %s in history: %v", batch.Name, err)
+	}
+}
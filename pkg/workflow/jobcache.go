@@ -0,0 +1,237 @@
+package operator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	template "github.com/Ziyang2go/workflowop/pkg/templates"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Indexes of the JobCache.
+const (
+	workflowIndex = "workflow"
+	phaseIndex    = "phase"
+)
+
+// Phases of a batch Job in the phase index of the JobCache.
+const (
+	jobActive    = "active"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+)
+
+// JobCache is a shared informer cache of the batch Jobs created by the
+// operator, indexed by workflow and by phase. Reads are served from memory instead of listing
+// every Job of the namespace. Its informer is the only watch of Jobs: the
+// changes it sees are passed on with OnChange.
+type JobCache struct {
+	informer cache.SharedIndexInformer
+
+	mu sync.Mutex
+	// created holds the Jobs created by the operator that the informer did
+	// not see yet, keyed by namespace/name, so that they count as active.
+	created map[string]*batchv1.Job
+}
+
+// NewJobCache returns a cache of the Jobs labelled with template.WorkflowLabel
+// in namespace. It is empty until Run is called.
+func NewJobCache(client kubernetes.Interface, namespace string, resyncPeriod time.Duration) *JobCache {
+	selector := func(options *metav1.ListOptions) {
+		options.LabelSelector = template.WorkflowLabel
+	}
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			selector(&options)
+			return client.BatchV1().Jobs(namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			selector(&options)
+			return client.BatchV1().Jobs(namespace).Watch(options)
+		},
+	}
+	informer := cache.NewSharedIndexInformer(lw, &batchv1.Job{}, resyncPeriod, cache.Indexers{
+		workflowIndex: indexJobByWorkflow,
+		phaseIndex:    indexJobByPhase,
+	})
+	c := &JobCache{informer: informer, created: make(map[string]*batchv1.Job)}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.seen(obj.(*batchv1.Job))
+		},
+	})
+	return c
+}
+
+// Created records a Job just created. It counts as active until the informer
+// sees it.
+func (c *JobCache) Created(job *batchv1.Job) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.created[job.Namespace+"/"+job.Name] = job
+}
+
+func (c *JobCache) seen(job *batchv1.Job) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.created, job.Namespace+"/"+job.Name)
+}
+
+// OnChange calls handle with every Job added, updated or deleted, and with
+// every cached Job on each resync. It must be called before Run.
+func (c *JobCache) OnChange(handle func(*batchv1.Job) error) {
+	c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			handle(obj.(*batchv1.Job))
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			handle(obj.(*batchv1.Job))
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if job, ok := obj.(*batchv1.Job); ok {
+				handle(job)
+			}
+		},
+	})
+}
+
+// Run fills the cache and keeps it up to date until stopCh is closed. It
+// returns once the initial list is in the cache, reporting whether it
+// succeeded.
+func (c *JobCache) Run(stopCh <-chan struct{}) bool {
+	go c.informer.Run(stopCh)
+	return cache.WaitForCacheSync(stopCh, c.informer.HasSynced)
+}
+
+// Get returns the cached Job, or nil if it is not in the cache.
+func (c *JobCache) Get(namespace, name string) (*batchv1.Job, error) {
+	obj, found, err := c.informer.GetIndexer().GetByKey(namespace + "/" + name)
+	if err != nil || !found {
+		return nil, err
+	}
+	return obj.(*batchv1.Job), nil
+}
+
+// ByWorkflow returns the cached Jobs of every attempt of every job of a
+// workflow.
+func (c *JobCache) ByWorkflow(namespace, workflow string) ([]*batchv1.Job, error) {
+	return c.byIndex(workflowIndex, namespace+"/"+workflow)
+}
+
+// Active returns the cached Jobs that did not finish yet, and the Jobs
+// created that are not in the cache yet.
+func (c *JobCache) Active() ([]*batchv1.Job, error) {
+	jobs, err := c.byIndex(phaseIndex, jobActive)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, job := range c.created {
+		if _, found, _ := c.informer.GetIndexer().GetByKey(key); found {
+			delete(c.created, key)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (c *JobCache) byIndex(index, value string) ([]*batchv1.Job, error) {
+	objs, err := c.informer.GetIndexer().ByIndex(index, value)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*batchv1.Job, 0, len(objs))
+	for _, obj := range objs {
+		jobs = append(jobs, obj.(*batchv1.Job))
+	}
+	return jobs, nil
+}
+
+func indexJobByWorkflow(obj interface{}) ([]string, error) {
+	job := obj.(*batchv1.Job)
+	workflow, found := job.Labels[template.WorkflowLabel]
+	if !found {
+		return nil, nil
+	}
+	return []string{job.Namespace + "/" + workflow}, nil
+}
+
+func indexJobByPhase(obj interface{}) ([]string, error) {
+	finished, succeeded := jobFinished(obj.(*batchv1.Job))
+	switch {
+	case !finished:
+		return []string{jobActive}, nil
+	case succeeded:
+		return []string{jobSucceeded}, nil
+	}
+	return []string{jobFailed}, nil
+}
+
+// LabelJobs adds the labels of template.JobLabels to the batch Jobs of
+// workflows that were created before Jobs were labelled, so that the job
+// cache, which only watches labelled Jobs, sees them finish. It must be
+// called before the job cache is run.
+func (w *WorkflowOp) LabelJobs() error {
+	jl, err := w.provider.ListJobs()
+	if err != nil {
+		return err
+	}
+	for i := range jl.Items {
+		job := &jl.Items[i]
+		if _, found := job.Labels[template.WorkflowLabel]; found {
+			continue
+		}
+		owners := job.GetOwnerReferences()
+		if len(owners) == 0 || owners[0].Kind != "Workflow" {
+			continue
+		}
+		wf, err := w.GetWorkflowByName(owners[0].Name, job.Namespace)
+		if kubeerr.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		migrateJobBatch(wf)
+		name := strings.TrimPrefix(job.Name, wf.Name+"-")
+		for jobName, batch := range wf.Status.JobBatch {
+			if batch != nil && batch.Name == job.Name {
+				name = jobName
+			}
+		}
+		wfJob := v1alpha.Job{Name: name}
+		for _, j := range allJobs(wf) {
+			if j.Name == name {
+				wfJob = j
+			}
+		}
+		if job.Labels == nil {
+			job.Labels = make(map[string]string)
+		}
+		for key, value := range template.JobLabels(wfJob, job.Name, wf) {
+			if _, found := job.Labels[key]; !found {
+				job.Labels[key] = value
+			}
+		}
+		job.TypeMeta = metav1.TypeMeta{Kind: "Job", APIVersion: "batch/v1"}
+		if err := w.provider.Update(job); err != nil {
+			return fmt.Errorf("failed to label job %s: %v", job.Name, err)
+		}
+		logrus.Printf("labelled job %s of workflow %s", job.Name, wf.Name)
+	}
+	return nil
+}
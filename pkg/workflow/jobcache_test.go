@@ -0,0 +1,282 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/kube"
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	template "github.com/Ziyang2go/workflowop/pkg/templates"
+	batchv1 "k8s.io/api/batch/v1"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// jobServer serves the batch Jobs of one namespace the way the API server
+// does, counting LIST requests. Watches stay open without events until the
+// client or the server stops them.
+type jobServer struct {
+	jobs  batchv1.JobList
+	lists int32
+	stop  chan struct{}
+}
+
+func newJobServer(namespace string, n int) *jobServer {
+	s := &jobServer{stop: make(chan struct{})}
+	s.jobs.Kind = "JobList"
+	s.jobs.APIVersion = "batch/v1"
+	s.jobs.ResourceVersion = "1"
+	for i := 0; i < n; i++ {
+		job := batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("wf-%d-job", i),
+				Namespace: namespace,
+				Labels: map[string]string{
+					template.WorkflowLabel:    fmt.Sprintf("wf-%d", i),
+					v1alpha.OrganizationLabel: "org",
+				},
+			},
+		}
+		if i%2 == 0 {
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: "True"}}
+		}
+		s.jobs.Items = append(s.jobs.Items, job)
+	}
+	return s
+}
+
+func (s *jobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Query().Get("watch") == "true" {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		select {
+		case <-s.stop:
+		case <-r.Context().Done():
+		}
+		return
+	}
+	atomic.AddInt32(&s.lists, 1)
+	json.NewEncoder(w).Encode(&s.jobs)
+}
+
+func (s *jobServer) Lists() int {
+	return int(atomic.LoadInt32(&s.lists))
+}
+
+// startJobCache serves n Jobs, half of them active, and returns a synced
+// cache of them that passes its changes to handle, unless it is nil.
+func startJobCache(tb testing.TB, n int, handle func(*batchv1.Job) error) (*JobCache, *jobServer, kubernetes.Interface, func()) {
+	server := newJobServer("ns", n)
+	srv := httptest.NewServer(server)
+	// No client side rate limit, which would dominate the benchmarks.
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: srv.URL, QPS: 1e6, Burst: 1e6})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	jobs := NewJobCache(client, "ns", time.Hour)
+	if handle != nil {
+		jobs.OnChange(handle)
+	}
+	stopCh := make(chan struct{})
+	if !jobs.Run(stopCh) {
+		tb.Fatal("job cache did not sync")
+	}
+	return jobs, server, client, func() {
+		close(stopCh)
+		close(server.stop)
+		srv.Close()
+	}
+}
+
+func TestJobCache(t *testing.T) {
+	var changed int32
+	jobs, server, _, stop := startJobCache(t, 10, func(*batchv1.Job) error {
+		atomic.AddInt32(&changed, 1)
+		return nil
+	})
+	defer stop()
+
+	active, err := jobs.Active()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 5 {
+		t.Errorf("Active() returned %d jobs, want 5", len(active))
+	}
+	for _, job := range active {
+		if finished, _ := jobFinished(job); finished {
+			t.Errorf("Active() returned finished job %s", job.Name)
+		}
+	}
+	job, err := jobs.Get("ns", "wf-3-job")
+	if err != nil || job == nil {
+		t.Fatalf("Get(wf-3-job) = %v, %v, want the job", job, err)
+	}
+	if job, err := jobs.Get("ns", "missing"); err != nil || job != nil {
+		t.Errorf("Get(missing) = %v, %v, want nil", job, err)
+	}
+	byWorkflow, err := jobs.ByWorkflow("ns", "wf-3")
+	if err != nil || len(byWorkflow) != 1 || byWorkflow[0].Name != "wf-3-job" {
+		t.Errorf("ByWorkflow(wf-3) = %v, %v, want wf-3-job", byWorkflow, err)
+	}
+	if byWorkflow, err := jobs.ByWorkflow("other", "wf-3"); err != nil || len(byWorkflow) != 0 {
+		t.Errorf("ByWorkflow() in another namespace = %v, %v, want none", byWorkflow, err)
+	}
+	if lists := server.Lists(); lists != 1 {
+		t.Errorf("the cache listed Jobs %d times, want once", lists)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&changed) != 10 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&changed); n != 10 {
+		t.Errorf("OnChange saw %d jobs, want 10", n)
+	}
+}
+
+func TestJobCacheCreated(t *testing.T) {
+	jobs, _, _, stop := startJobCache(t, 10, nil)
+	defer stop()
+
+	// The watch of the test server sends no events: the new Job is never
+	// seen by the informer, wf-1-job already is.
+	jobs.Created(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "wf-new-job", Namespace: "ns"}})
+	jobs.Created(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "wf-1-job", Namespace: "ns"}})
+	for i := 0; i < 2; i++ {
+		active, err := jobs.Active()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(active) != 6 {
+			t.Errorf("Active() returned %d jobs, want the 5 cached and the new one", len(active))
+		}
+	}
+}
+
+// labelProvider lists batch Jobs, serves workflows and records the Jobs
+// updated. The other Provider methods are not implemented.
+type labelProvider struct {
+	kube.Provider
+	jobs      []batchv1.Job
+	workflows map[string]*v1alpha.Workflow
+	updated   map[string]*batchv1.Job
+}
+
+func (p *labelProvider) ListJobs() (*batchv1.JobList, error) {
+	return &batchv1.JobList{Items: p.jobs}, nil
+}
+
+func (p *labelProvider) Get(object runtime.Object) error {
+	wf := object.(*v1alpha.Workflow)
+	stored, found := p.workflows[wf.Name]
+	if !found {
+		return kubeerr.NewNotFound(schema.GroupResource{Group: "threekit.com", Resource: "workflows"}, wf.Name)
+	}
+	stored.DeepCopyInto(wf)
+	return nil
+}
+
+func (p *labelProvider) Update(object runtime.Object) error {
+	job := object.(*batchv1.Job)
+	p.updated[job.Name] = job.DeepCopy()
+	return nil
+}
+
+func TestLabelJobs(t *testing.T) {
+	owned := func(name, workflow string, labels map[string]string) batchv1.Job {
+		return batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "ns",
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Workflow", Name: workflow}},
+		}}
+	}
+	// wf was created by an operator that stored the batch references in
+	// the spec and labelled Jobs with their name only.
+	wf := &v1alpha.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "wf", Namespace: "ns", Labels: map[string]string{v1alpha.OrganizationLabel: "acme"}},
+		Spec: v1alpha.WorkflowSpec{JobBatch: map[string]*v1alpha.BatchReference{
+			"import": {Kind: "Job", Name: "wf-import"},
+		}},
+		Inputs: v1alpha.WorkflowInputs{Jobs: []v1alpha.Job{{Name: "import", Type: "import"}}},
+	}
+	labelled := owned("wf-render", "wf", map[string]string{template.WorkflowLabel: "wf"})
+	p := &labelProvider{
+		jobs: []batchv1.Job{
+			owned("wf-import", "wf", map[string]string{"name": "wf-import"}),
+			labelled,
+			owned("gone-import", "gone", nil),
+			{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns"}},
+		},
+		workflows: map[string]*v1alpha.Workflow{"wf": wf},
+		updated:   make(map[string]*batchv1.Job),
+	}
+	w := &WorkflowOp{provider: p}
+	if err := w.LabelJobs(); err != nil {
+		t.Fatalf("LabelJobs() failed: %v", err)
+	}
+	if len(p.updated) != 1 {
+		t.Fatalf("LabelJobs() updated %d Jobs, want only wf-import", len(p.updated))
+	}
+	want := map[string]string{
+		"name":                    "wf-import",
+		template.WorkflowLabel:    "wf",
+		template.JobLabel:         "import",
+		template.JobTypeLabel:     "import",
+		v1alpha.OrganizationLabel: "acme",
+	}
+	if got := p.updated["wf-import"].Labels; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+}
+
+// BenchmarkJobCacheActive reads the active Jobs from the cache, the way every
+// reconcile counts the quota usage. The Jobs are listed once, when the cache
+// syncs.
+func BenchmarkJobCacheActive(b *testing.B) {
+	jobs, server, _, stop := startJobCache(b, 1000, nil)
+	defer stop()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jobs.Active(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	if lists := server.Lists(); lists != 1 {
+		b.Fatalf("listed Jobs %d times, want once", lists)
+	}
+	b.ReportMetric(float64(server.Lists())/float64(b.N), "lists/op")
+}
+
+// BenchmarkListJobs lists the Jobs from the API server on every read, as the
+// operator does without a cache.
+func BenchmarkListJobs(b *testing.B) {
+	_, server, client, stop := startJobCache(b, 1000, nil)
+	defer stop()
+
+	before := server.Lists()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		jl, err := client.BatchV1().Jobs("ns").List(metav1.ListOptions{LabelSelector: template.WorkflowLabel})
+		if err != nil {
+			b.Fatal(err)
+		}
+		for j := range jl.Items {
+			jobFinished(&jl.Items[j])
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(server.Lists()-before)/float64(b.N), "lists/op")
+}
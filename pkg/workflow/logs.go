@@ -0,0 +1,124 @@
+package operator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Ziyang2go/workflowop/pkg/logsink"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultLogTailBytes is the amount of job log kept inline in the workflow
+// when no other size is configured.
+const DefaultLogTailBytes = 4096
+
+// GetJobLogs returns the tail of the logs of pod, the most recent pod run by
+// job. When a log sink is configured the complete log is archived as well and
+// the archived object is returned.
+func (w *WorkflowOp) GetJobLogs(job *batchv1.Job, pod *corev1.Pod) (string, *logsink.Object, error) {
+	if pod == nil {
+		return "", nil, nil
+	}
+	rc, err := w.StreamPodLogs(pod)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rc.Close()
+	tail := newTailBuffer(w.logTailBytes)
+	if w.logSink == nil {
+		_, err = io.Copy(tail, rc)
+		return tail.String(), nil, err
+	}
+	key := job.Namespace + "/" + job.Name + "/" + pod.Name + ".log"
+	archive, err := w.logSink.Put(key, io.TeeReader(rc, tail))
+	return tail.String(), archive, err
+}
+
+// GetJobPod finds the most recently created pod of job. Pods are matched by
+// the controller-uid label the Job controller sets, or by job-name when the
+// job has no UID yet.
+func (w *WorkflowOp) GetJobPod(job *batchv1.Job) (*corev1.Pod, error) {
+	selector := "job-name=" + job.Name
+	if job.UID != "" {
+		selector = "controller-uid=" + string(job.UID)
+	}
+	client := w.provider.GetKubeClient()
+	pods, err := client.CoreV1().Pods(job.Namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	var latest *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if latest == nil || latest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			latest = pod
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no pod found for job %s", job.Name)
+	}
+	return latest, nil
+}
+
+// terminationMessage returns the termination message of the job container
+// of pod, or "" when there is none.
+func terminationMessage(pod *corev1.Pod) string {
+	if pod == nil {
+		return ""
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == "job" && status.State.Terminated != nil {
+			return status.State.Terminated.Message
+		}
+	}
+	return ""
+}
+
+// StreamPodLogs opens the log stream of the first container of pod. The
+// caller must close the returned reader.
+func (w *WorkflowOp) StreamPodLogs(pod *corev1.Pod) (io.ReadCloser, error) {
+	client := w.provider.GetKubeClient()
+	logOptions := &corev1.PodLogOptions{}
+	if len(pod.Spec.Containers) > 0 {
+		logOptions.Container = pod.Spec.Containers[0].Name
+	}
+	req := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOptions)
+	return req.Stream()
+}
+
+// tailBuffer is an io.Writer that only remembers the last size bytes written
+// to it.
+type tailBuffer struct {
+	size int
+	buf  []byte
+	cut  bool
+}
+
+func newTailBuffer(size int) *tailBuffer {
+	return &tailBuffer{size: size}
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if t.size <= 0 {
+		t.cut = t.cut || n > 0
+		return n, nil
+	}
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - t.size; over > 0 {
+		t.buf = append(t.buf[:0], t.buf[over:]...)
+		t.cut = true
+	}
+	return n, nil
+}
+
+// String returns the buffered tail, prefixed with a marker if the beginning
+// of the log was dropped.
+func (t *tailBuffer) String() string {
+	if t.cut {
+		return "...(truncated)\n" + string(t.buf)
+	}
+	return string(t.buf)
+}
@@ -0,0 +1,66 @@
+package operator
+
+import (
+	"github.com/Ziyang2go/workflowop/pkg/logsink"
+	"github.com/Ziyang2go/workflowop/pkg/quota"
+	template "github.com/Ziyang2go/workflowop/pkg/templates"
+)
+
+// Option configures optional collaborators of a WorkflowOp.
+type Option func(*WorkflowOp)
+
+// WithRegistry sets the registry used to resolve job types. The job type
+// ConfigMap named configMapName is loaded into it whenever it changes.
+func WithRegistry(registry *template.Registry, configMapName string) Option {
+	return func(w *WorkflowOp) {
+		w.registry = registry
+		w.jobTypesConfigMap = configMapName
+	}
+}
+
+// WithLogTailBytes sets how many bytes from the end of a job log are kept in
+// the workflow's BatchReference.Logs.
+func WithLogTailBytes(n int) Option {
+	return func(w *WorkflowOp) {
+		w.logTailBytes = n
+	}
+}
+
+// WithLogSink archives the complete log of every finished job to sink.
+func WithLogSink(sink logsink.Sink) Option {
+	return func(w *WorkflowOp) {
+		w.logSink = sink
+	}
+}
+
+// WithHistory records every job in store when it is created and finished.
+func WithHistory(store HistoryStore) Option {
+	return func(w *WorkflowOp) {
+		w.history = store
+	}
+}
+
+// WithRetention sets how long finished workflows are kept by default.
+func WithRetention(retention Retention) Option {
+	return func(w *WorkflowOp) {
+		w.retention = retention
+	}
+}
+
+// WithQuota sets the scheduler admitting jobs within the per organization
+// quotas. The quota ConfigMap named configMapName is loaded into it whenever
+// it changes.
+func WithQuota(scheduler *quota.Scheduler, configMapName string) Option {
+	return func(w *WorkflowOp) {
+		w.quota = scheduler
+		w.quotaConfigMap = configMapName
+	}
+}
+
+// WithJobCache reads the batch Jobs of the operator from cache instead of
+// listing them from the API server.
+func WithJobCache(jobs *JobCache) Option {
+	return func(w *WorkflowOp) {
+		w.jobs = jobs
+	}
+}
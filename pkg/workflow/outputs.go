@@ -0,0 +1,92 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+)
+
+// ResultOutput refers to the whole result of a job when it has no output of
+// that name.
+const ResultOutput = "result"
+
+var outputPattern = regexp.MustCompile(`\{\{\s*jobs\.([^.{}\s]+)\.outputs\.([A-Za-z0-9_-]+)\s*\}\}`)
+
+// parseOutputs returns the fields of result when it is a JSON object. String
+// values are kept as is, other values as JSON.
+func parseOutputs(result string) map[string]string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(result), &fields); err != nil || len(fields) == 0 {
+		return nil
+	}
+	outputs := make(map[string]string, len(fields))
+	for name, value := range fields {
+		var s string
+		if json.Unmarshal(value, &s) == nil {
+			outputs[name] = s
+		} else {
+			outputs[name] = string(value)
+		}
+	}
+	return outputs
+}
+
+// outputReferences returns the names of the jobs whose outputs data refers
+// to.
+func outputReferences(data string) []string {
+	var jobs []string
+	for _, match := range outputPattern.FindAllStringSubmatch(data, -1) {
+		jobs = append(jobs, match[1])
+	}
+	return jobs
+}
+
+// resolveOutputs replaces every {{jobs.<name>.outputs.<field>}} in data by
+// the output of the named job. String outputs are JSON escaped, to be quoted
+// in data like "{{jobs.import.outputs.assetId}}", other outputs are inserted
+// as JSON. It fails when the job did not succeed or has no such output.
+func resolveOutputs(data string, wf *v1alpha.Workflow) (string, error) {
+	var err error
+	result := outputPattern.ReplaceAllStringFunc(data, func(ref string) string {
+		match := outputPattern.FindStringSubmatch(ref)
+		job, output := match[1], match[2]
+		batch := wf.Status.JobBatch[job]
+		if batch == nil || wf.Status.JobStatus[job] != v1alpha.JobOK {
+			err = fmt.Errorf("%s: job %s did not succeed", ref, job)
+			return ref
+		}
+		value, found := outputValue(batch.Result, output)
+		if !found {
+			err = fmt.Errorf("%s: job %s has no output %s", ref, job, output)
+			return ref
+		}
+		var s string
+		if json.Unmarshal(value, &s) == nil {
+			return jsonEscape(s)
+		}
+		return string(value)
+	})
+	return result, err
+}
+
+// outputValue returns the named output of a job that ended with result, as
+// JSON. The whole result is the ResultOutput unless it has a field of that
+// name; a result that is not JSON is returned as a JSON string.
+func outputValue(result, output string) (json.RawMessage, bool) {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal([]byte(result), &fields) == nil {
+		if value, found := fields[output]; found {
+			return value, true
+		}
+	}
+	if output != ResultOutput {
+		return nil, false
+	}
+	if json.Valid([]byte(result)) {
+		return json.RawMessage(result), true
+	}
+	quoted, _ := json.Marshal(result)
+	return quoted, true
+}
@@ -0,0 +1,57 @@
+package operator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+)
+
+func TestResolveOutputs(t *testing.T) {
+	wf := &v1alpha.Workflow{Status: v1alpha.WorkflowStatus{
+		JobStatus: map[string]v1alpha.JobPhase{
+			"import":  v1alpha.JobOK,
+			"list":    v1alpha.JobOK,
+			"log":     v1alpha.JobOK,
+			"running": v1alpha.JobWorking,
+		},
+		JobBatch: map[string]*v1alpha.BatchReference{
+			"import":  {Result: `{"assetId":"a\"1\\","size":3,"tags":["x"]}`},
+			"list":    {Result: `["scene-1","scene-2"]`},
+			"log":     {Result: "done \"ok\"\n"},
+			"running": {},
+		},
+	}}
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{name: "escaped string", data: `{"assetId":"{{jobs.import.outputs.assetId}}"}`, want: `{"assetId":"a\"1\\"}`},
+		{name: "number", data: `{"size":{{ jobs.import.outputs.size }}}`, want: `{"size":3}`},
+		{name: "array", data: `{"tags":{{jobs.import.outputs.tags}}}`, want: `{"tags":["x"]}`},
+		{name: "whole result", data: `{"scenes":{{jobs.list.outputs.result}}}`, want: `{"scenes":["scene-1","scene-2"]}`},
+		{name: "text result", data: `{"log":"{{jobs.log.outputs.result}}"}`, want: `{"log":"done \"ok\"\n"}`},
+		{name: "missing output", data: `{{jobs.import.outputs.missing}}`, wantErr: true},
+		{name: "unfinished job", data: `{{jobs.running.outputs.result}}`, wantErr: true},
+		{name: "unknown job", data: `{{jobs.export.outputs.result}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveOutputs(tt.data, wf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveOutputs() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("resolveOutputs() = %s, want %s", got, tt.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("resolveOutputs() = %s, which is not valid JSON", got)
+			}
+		})
+	}
+}
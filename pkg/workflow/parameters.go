@@ -0,0 +1,100 @@
+package operator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+)
+
+var parameterPattern = regexp.MustCompile(`\{\{\s*workflow\.parameters\.([A-Za-z0-9_-]+)\s*\}\}`)
+
+// ResolveParameters returns the value of every parameter of wf, falling back
+// to defaults. It fails when a parameter is declared twice or has neither a
+// value nor a default, or when a job refers to an undeclared parameter.
+func ResolveParameters(wf *v1alpha.Workflow) (map[string]string, error) {
+	params := make(map[string]string, len(wf.Spec.Arguments.Parameters))
+	for _, param := range wf.Spec.Arguments.Parameters {
+		if _, found := params[param.Name]; found {
+			return nil, fmt.Errorf("duplicate parameter %s", param.Name)
+		}
+		switch {
+		case param.Value != nil:
+			params[param.Name] = *param.Value
+		case param.Default != nil:
+			params[param.Name] = *param.Default
+		default:
+			return nil, fmt.Errorf("parameter %s is required", param.Name)
+		}
+	}
+	for _, job := range wf.Inputs.Jobs {
+		fields := append([]string{job.Name, job.Data}, job.DependsOn...)
+		for _, field := range fields {
+			for _, match := range parameterPattern.FindAllStringSubmatch(field, -1) {
+				if _, found := params[match[1]]; !found {
+					return nil, fmt.Errorf("job %s refers to undeclared parameter %s", job.Name, match[1])
+				}
+			}
+		}
+		if job.When == "" {
+			continue
+		}
+		expr, err := parseWhen(job.When)
+		if err != nil {
+			continue
+		}
+		for _, ref := range expr.refs() {
+			name := strings.TrimPrefix(ref, "workflow.parameters.")
+			if _, found := params[name]; name != ref && !found {
+				return nil, fmt.Errorf("job %s refers to undeclared parameter %s", job.Name, name)
+			}
+		}
+	}
+	return params, nil
+}
+
+// substituteParameters replaces every {{workflow.parameters.<name>}} of s by
+// the parameter value. Unknown parameters are left as is.
+func substituteParameters(s string, params map[string]string) string {
+	return replaceParameters(s, params, func(value string) string { return value })
+}
+
+// substituteDataParameters is substituteParameters for job data. Values are
+// JSON escaped, to be quoted in data like "{{workflow.parameters.<name>}}".
+func substituteDataParameters(data string, params map[string]string) string {
+	return replaceParameters(data, params, jsonEscape)
+}
+
+func replaceParameters(s string, params map[string]string, escape func(string) string) string {
+	return parameterPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if value, found := params[parameterPattern.FindStringSubmatch(ref)[1]]; found {
+			return escape(value)
+		}
+		return ref
+	})
+}
+
+// WorkflowJobs returns the jobs of wf with parameters substituted in their
+// name, dependencies and data; values are JSON escaped in data. The jobs are returned unchanged when the
+// parameters do not resolve; HandlePendingWf rejects such workflows.
+func WorkflowJobs(wf *v1alpha.Workflow) []v1alpha.Job {
+	params, err := ResolveParameters(wf)
+	if err != nil || len(params) == 0 {
+		return wf.Inputs.Jobs
+	}
+	jobs := make([]v1alpha.Job, 0, len(wf.Inputs.Jobs))
+	for _, job := range wf.Inputs.Jobs {
+		job.Name = substituteParameters(job.Name, params)
+		job.Data = substituteDataParameters(job.Data, params)
+		if job.DependsOn != nil {
+			deps := make([]string, 0, len(job.DependsOn))
+			for _, dep := range job.DependsOn {
+				deps = append(deps, substituteParameters(dep, params))
+			}
+			job.DependsOn = deps
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
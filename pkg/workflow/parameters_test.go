@@ -0,0 +1,150 @@
+package operator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestResolveParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []v1alpha.Parameter
+		jobs    []v1alpha.Job
+		want    map[string]string
+		wantErr string
+	}{
+		{name: "none", want: map[string]string{}},
+		{
+			name: "values and defaults",
+			params: []v1alpha.Parameter{
+				{Name: "scene", Value: strPtr("s1"), Default: strPtr("s0")},
+				{Name: "quality", Default: strPtr("low")},
+				{Name: "empty", Value: strPtr("")},
+			},
+			jobs: []v1alpha.Job{{
+				Name:      "render-{{workflow.parameters.scene}}",
+				Data:      `{"quality":"{{ workflow.parameters.quality }}"}`,
+				DependsOn: []string{"import-{{workflow.parameters.empty}}"},
+				When:      `workflow.parameters.quality == "low"`,
+			}},
+			want: map[string]string{"scene": "s1", "quality": "low", "empty": ""},
+		},
+		{
+			name:    "duplicate",
+			params:  []v1alpha.Parameter{{Name: "scene", Value: strPtr("s1")}, {Name: "scene", Value: strPtr("s2")}},
+			wantErr: "duplicate parameter scene",
+		},
+		{name: "required", params: []v1alpha.Parameter{{Name: "scene"}}, wantErr: "parameter scene is required"},
+		{
+			name:    "undeclared in data",
+			jobs:    []v1alpha.Job{{Name: "render", Data: `{"scene":"{{workflow.parameters.scene}}"}`}},
+			wantErr: "job render refers to undeclared parameter scene",
+		},
+		{
+			name:    "undeclared in a dependency",
+			jobs:    []v1alpha.Job{{Name: "render", DependsOn: []string{"import-{{workflow.parameters.scene}}"}}},
+			wantErr: "job render refers to undeclared parameter scene",
+		},
+		{
+			name:    "undeclared in when",
+			jobs:    []v1alpha.Job{{Name: "render", When: `workflow.parameters.scene != ""`}},
+			wantErr: "job render refers to undeclared parameter scene",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &v1alpha.Workflow{
+				Spec:   v1alpha.WorkflowSpec{Arguments: v1alpha.Arguments{Parameters: tt.params}},
+				Inputs: v1alpha.WorkflowInputs{Jobs: tt.jobs},
+			}
+			got, err := ResolveParameters(wf)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveParameters() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveParameters() failed: %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ResolveParameters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubstituteParameters(t *testing.T) {
+	params := map[string]string{"scene": "s1", "quality": "high"}
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"render", "render"},
+		{"render-{{workflow.parameters.scene}}", "render-s1"},
+		{`{"q":"{{ workflow.parameters.quality }}","s":"{{workflow.parameters.scene}}"}`, `{"q":"high","s":"s1"}`},
+		{"{{workflow.parameters.missing}}", "{{workflow.parameters.missing}}"},
+		{"{{jobs.import.outputs.result}}", "{{jobs.import.outputs.result}}"},
+	}
+	for _, tt := range tests {
+		if got := substituteParameters(tt.s, params); got != tt.want {
+			t.Errorf("substituteParameters(%s) = %s, want %s", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestSubstituteDataParameters(t *testing.T) {
+	params := map[string]string{"scene": `a "quoted" \ name`, "line": "one\ntwo"}
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"scene":"{{workflow.parameters.scene}}"}`, `{"scene":"a \"quoted\" \\ name"}`},
+		{`{"lines":"{{ workflow.parameters.line }}"}`, `{"lines":"one\ntwo"}`},
+		{`{"missing":"{{workflow.parameters.missing}}"}`, `{"missing":"{{workflow.parameters.missing}}"}`},
+	}
+	for _, tt := range tests {
+		if got := substituteDataParameters(tt.data, params); got != tt.want {
+			t.Errorf("substituteDataParameters(%s) = %s, want %s", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestWorkflowJobs(t *testing.T) {
+	jobs := []v1alpha.Job{
+		{Name: "import-{{workflow.parameters.scene}}", Data: `{"scene":"{{workflow.parameters.scene}}"}`},
+		{Name: "render", DependsOn: []string{"import-{{workflow.parameters.scene}}"}},
+	}
+	wf := &v1alpha.Workflow{
+		Spec:   v1alpha.WorkflowSpec{Arguments: v1alpha.Arguments{Parameters: []v1alpha.Parameter{{Name: "scene", Default: strPtr("s1")}}}},
+		Inputs: v1alpha.WorkflowInputs{Jobs: jobs},
+	}
+	got := WorkflowJobs(wf)
+	if got[0].Name != "import-s1" || got[0].Data != `{"scene":"s1"}` || got[1].DependsOn[0] != "import-s1" {
+		t.Errorf("WorkflowJobs() = %+v, want the parameters substituted", got)
+	}
+
+	// Values are escaped in the data only.
+	wf.Spec.Arguments.Parameters[0].Value = strPtr(`s"1`)
+	got = WorkflowJobs(wf)
+	if got[0].Name != `import-s"1` || got[0].Data != `{"scene":"s\"1"}` || got[1].DependsOn[0] != `import-s"1` {
+		t.Errorf("WorkflowJobs() = %+v, want the parameters escaped in the data", got)
+	}
+	wf.Spec.Arguments.Parameters[0].Value = nil
+	if wf.Inputs.Jobs[0].Name != "import-{{workflow.parameters.scene}}" || wf.Inputs.Jobs[1].DependsOn[0] != "import-{{workflow.parameters.scene}}" {
+		t.Errorf("WorkflowJobs() changed the workflow jobs to %+v", wf.Inputs.Jobs)
+	}
+
+	// The jobs are returned as is when the parameters do not resolve.
+	wf.Spec.Arguments.Parameters = nil
+	if got := WorkflowJobs(wf); got[0].Name != jobs[0].Name {
+		t.Errorf("WorkflowJobs() = %+v without parameters, want the jobs unchanged", got)
+	}
+}
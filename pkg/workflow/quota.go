@@ -0,0 +1,61 @@
+package operator
+
+import (
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/Ziyang2go/workflowop/pkg/quota"
+	template "github.com/Ziyang2go/workflowop/pkg/templates"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// DefaultQuotaStaleAfter is how long a queued job keeps its place in the
+// quota queue without its workflow being handled again. It spans a few resync
+// periods.
+const DefaultQuotaStaleAfter = 2 * time.Minute
+
+// jobUsage counts the unfinished batch Jobs created by the operator per
+// organization and job type. They are read from the job cache when there is
+// one, which counts the Jobs just created that it did not see yet, and listed
+// otherwise.
+func (w *WorkflowOp) jobUsage() (quota.Usage, error) {
+	usage := quota.NewUsage()
+	var active []*batchv1.Job
+	if w.jobs != nil {
+		jobs, err := w.jobs.Active()
+		if err != nil {
+			return usage, err
+		}
+		active = jobs
+	} else {
+		jl, err := w.provider.ListJobs()
+		if err != nil {
+			return usage, err
+		}
+		for i := range jl.Items {
+			job := &jl.Items[i]
+			if _, found := job.Labels[template.WorkflowLabel]; !found {
+				continue
+			}
+			if finished, _ := jobFinished(job); !finished {
+				active = append(active, job)
+			}
+		}
+	}
+	for _, job := range active {
+		usage.Add(job.Labels[v1alpha.OrganizationLabel], job.Labels[template.JobTypeLabel])
+	}
+	return usage, nil
+}
+
+// quotaTicket identifies job in the quota queue. Jobs of higher priority
+// workflows are admitted first, then jobs of older workflows.
+func quotaTicket(job v1alpha.Job, wf *v1alpha.Workflow) quota.Ticket {
+	return quota.Ticket{
+		Key:          wf.Namespace + "/" + wf.Name + "/" + job.Name,
+		Organization: wf.GetLabels()[v1alpha.OrganizationLabel],
+		Type:         job.Type,
+		Priority:     wf.Spec.Priority,
+		Created:      wf.CreationTimestamp.Time,
+	}
+}
@@ -0,0 +1,118 @@
+package operator
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// retryStrategyFor returns the retry strategy of the named job, or of the
+// fan-out job it is a child of, falling back to the workflow strategy. It
+// returns nil when the job is never retried.
+func retryStrategyFor(name string, wf *v1alpha.Workflow) *v1alpha.RetryStrategy {
+	for _, job := range WorkflowJobs(wf) {
+		if job.Name == name && job.RetryStrategy != nil {
+			return job.RetryStrategy
+		}
+	}
+	if parent, found := fanOutParent(name, wf); found && parent.RetryStrategy != nil {
+		return parent.RetryStrategy
+	}
+	return wf.Inputs.RetryStrategy
+}
+
+// attemptName is the batch Job name of the given attempt of a job. The first
+// attempt keeps the plain name.
+func attemptName(batchName string, attempt int) string {
+	if attempt == 0 {
+		return batchName
+	}
+	return batchName + "-" + strconv.Itoa(attempt)
+}
+
+// jobFinished reports whether job reached its Complete or Failed condition.
+func jobFinished(job *batchv1.Job) (finished, succeeded bool) {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return true, true
+		case batchv1.JobFailed:
+			return true, false
+		}
+	}
+	return false, false
+}
+
+// FailureReason classifies why job failed, looking at the state of its last
+// pod when there is one.
+func FailureReason(job *batchv1.Job, pod *corev1.Pod) string {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Reason == "DeadlineExceeded" {
+			return v1alpha.FailureReasonDeadlineExceeded
+		}
+	}
+	if pod == nil {
+		return v1alpha.FailureReasonError
+	}
+	if pod.Status.Reason == "Evicted" {
+		return v1alpha.FailureReasonEvicted
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if t := cs.State.Terminated; t != nil && t.Reason == "OOMKilled" {
+			return v1alpha.FailureReasonOOMKilled
+		}
+	}
+	return v1alpha.FailureReasonError
+}
+
+// shouldRetry reports whether a job that already made retries retries and
+// then failed for reason may be attempted again.
+func shouldRetry(strategy *v1alpha.RetryStrategy, retries int, reason string) bool {
+	if strategy == nil || retries >= int(strategy.Limit) {
+		return false
+	}
+	if len(strategy.RetryOn) == 0 {
+		return true
+	}
+	for _, r := range strategy.RetryOn {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay is how long to wait before the retry-th retry (starting at 1).
+func retryDelay(strategy *v1alpha.RetryStrategy, retry int) time.Duration {
+	if strategy == nil || strategy.Backoff == nil || strategy.Backoff.Duration == "" {
+		return 0
+	}
+	backoff := strategy.Backoff
+	delay, err := time.ParseDuration(backoff.Duration)
+	if err != nil {
+		logrus.Errorf("invalid backoff duration %q: %v", backoff.Duration, err)
+		return 0
+	}
+	factor := float64(backoff.Factor)
+	if factor < 1 {
+		factor = 1
+	}
+	delay = time.Duration(float64(delay) * math.Pow(factor, float64(retry-1)))
+	if backoff.MaxDuration != "" {
+		max, err := time.ParseDuration(backoff.MaxDuration)
+		if err != nil {
+			logrus.Errorf("invalid backoff max duration %q: %v", backoff.MaxDuration, err)
+		} else if delay > max || delay < 0 {
+			delay = max
+		}
+	}
+	return delay
+}
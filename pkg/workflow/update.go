@@ -0,0 +1,99 @@
+package operator
+
+import (
+	"reflect"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/sirupsen/logrus"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// UpdateWorkflow writes the status changes made in wf, a modified copy of
+// orig, through the status subresource. When the write conflicts with another
+// update the latest workflow is fetched and the same changes are applied to
+// it again, so that concurrent updates of other jobs are never lost.
+func (w *WorkflowOp) UpdateWorkflow(orig, wf *v1alpha.Workflow) error {
+	latest := wf.DeepCopy()
+	first := true
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			current, err := w.GetWorkflowByName(wf.Name, wf.Namespace)
+			if err != nil {
+				return err
+			}
+			logrus.Printf("workflow %s changed concurrently, reapplying status update", wf.Name)
+			latest = current
+			mergeStatus(&latest.Status, &orig.Status, &wf.Status)
+		}
+		first = false
+		if latest.Status.Status.Finished() && latest.Status.FinishedAt == nil {
+			now := metav1.Now()
+			latest.Status.FinishedAt = &now
+		}
+		latest.Status.ObservedGeneration = latest.Generation
+		err := w.provider.UpdateStatus(latest)
+		if err != nil && !kubeerr.IsConflict(err) {
+			logrus.Errorf("failed to update status of workflow %s: %v", wf.Name, err)
+		}
+		return err
+	})
+}
+
+// mergeStatus applies to latest every change between orig and modified. Job
+// entries are merged one by one; other fields are taken from modified when
+// they were changed.
+func mergeStatus(latest, orig, modified *v1alpha.WorkflowStatus) {
+	if modified.Status != orig.Status {
+		latest.Status = modified.Status
+	}
+	if !reflect.DeepEqual(modified.StartedAt, orig.StartedAt) {
+		latest.StartedAt = modified.StartedAt
+	}
+	if !reflect.DeepEqual(modified.FinishedAt, orig.FinishedAt) {
+		latest.FinishedAt = modified.FinishedAt
+	}
+	if !reflect.DeepEqual(modified.WorkflowTemplate, orig.WorkflowTemplate) {
+		latest.WorkflowTemplate = modified.WorkflowTemplate
+	}
+	for name, phase := range modified.JobStatus {
+		if orig.JobStatus[name] != phase {
+			if latest.JobStatus == nil {
+				latest.JobStatus = make(map[string]v1alpha.JobPhase)
+			}
+			latest.JobStatus[name] = phase
+		}
+	}
+	for name, batch := range modified.JobBatch {
+		if !reflect.DeepEqual(orig.JobBatch[name], batch) {
+			if latest.JobBatch == nil {
+				latest.JobBatch = make(map[string]*v1alpha.BatchReference)
+			}
+			latest.JobBatch[name] = batch
+		}
+	}
+	for name, fanOut := range modified.FanOut {
+		if prev, found := orig.FanOut[name]; !found || prev != fanOut {
+			if latest.FanOut == nil {
+				latest.FanOut = make(map[string]v1alpha.FanOutStatus)
+			}
+			latest.FanOut[name] = fanOut
+		}
+	}
+	for _, cond := range modified.Conditions {
+		if prev := GetCondition(orig, cond.Type); prev == nil || !reflect.DeepEqual(*prev, cond) {
+			SetCondition(latest, cond.Type, cond.Status, cond.Reason, cond.Message)
+		}
+	}
+}
+
+// migrateJobBatch moves the batch references of workflows started by older
+// operators, which kept them in the spec, into the status.
+func migrateJobBatch(wf *v1alpha.Workflow) bool {
+	if len(wf.Status.JobBatch) > 0 || wf.Spec.JobBatch == nil {
+		return false
+	}
+	wf.Status.JobBatch = wf.Spec.JobBatch
+	return true
+}
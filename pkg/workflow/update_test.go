@@ -0,0 +1,143 @@
+package operator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMergeStatus(t *testing.T) {
+	base := func() *v1alpha.WorkflowStatus {
+		return &v1alpha.WorkflowStatus{
+			Status:    v1alpha.WorkflowWorking,
+			JobStatus: map[string]v1alpha.JobPhase{"a": v1alpha.JobWorking, "b": v1alpha.JobWorking},
+			JobBatch: map[string]*v1alpha.BatchReference{
+				"a": {Kind: "Job", Name: "wf-a"},
+				"b": {Kind: "Job", Name: "wf-b"},
+			},
+			FanOut: map[string]v1alpha.FanOutStatus{
+				"a": {Total: 2, Running: 2},
+				"b": {Total: 3, Running: 3},
+			},
+			Conditions: []v1alpha.WorkflowCondition{
+				{Type: v1alpha.WorkflowAdmitted, Status: corev1.ConditionTrue, Reason: "Admitted"},
+				{Type: v1alpha.WorkflowSuspended, Status: corev1.ConditionFalse, Reason: v1alpha.ReasonResumed},
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		// concurrent is the change made by the update that won, modify the
+		// change made by the update that is merged.
+		concurrent func(*v1alpha.WorkflowStatus)
+		modify     func(*v1alpha.WorkflowStatus)
+		want       func(*v1alpha.WorkflowStatus)
+	}{
+		{
+			name: "other jobs",
+			concurrent: func(s *v1alpha.WorkflowStatus) {
+				s.JobStatus["b"] = v1alpha.JobOK
+				s.JobBatch["b"] = &v1alpha.BatchReference{Kind: "Job", Name: "wf-b", Result: "b done"}
+			},
+			modify: func(s *v1alpha.WorkflowStatus) {
+				s.JobStatus["a"] = v1alpha.JobFailed
+				s.JobBatch["a"] = &v1alpha.BatchReference{Kind: "Job", Name: "wf-a", Logs: "a failed"}
+				s.JobStatus["c"] = v1alpha.JobWorking
+				s.JobBatch["c"] = &v1alpha.BatchReference{Kind: "Job", Name: "wf-c"}
+			},
+			want: func(s *v1alpha.WorkflowStatus) {
+				s.JobStatus["a"] = v1alpha.JobFailed
+				s.JobStatus["b"] = v1alpha.JobOK
+				s.JobStatus["c"] = v1alpha.JobWorking
+				s.JobBatch["a"] = &v1alpha.BatchReference{Kind: "Job", Name: "wf-a", Logs: "a failed"}
+				s.JobBatch["b"] = &v1alpha.BatchReference{Kind: "Job", Name: "wf-b", Result: "b done"}
+				s.JobBatch["c"] = &v1alpha.BatchReference{Kind: "Job", Name: "wf-c"}
+			},
+		},
+		{
+			name: "same job",
+			concurrent: func(s *v1alpha.WorkflowStatus) {
+				s.JobStatus["a"] = v1alpha.JobOK
+			},
+			modify: func(s *v1alpha.WorkflowStatus) {
+				s.JobStatus["a"] = v1alpha.JobFailed
+			},
+			want: func(s *v1alpha.WorkflowStatus) {
+				s.JobStatus["a"] = v1alpha.JobFailed
+			},
+		},
+		{
+			name: "fan-out",
+			concurrent: func(s *v1alpha.WorkflowStatus) {
+				s.FanOut["b"] = v1alpha.FanOutStatus{Total: 3, Succeeded: 1, Running: 2}
+			},
+			modify: func(s *v1alpha.WorkflowStatus) {
+				s.FanOut["a"] = v1alpha.FanOutStatus{Total: 2, Failed: 1, Running: 1}
+				s.FanOut["c"] = v1alpha.FanOutStatus{Message: "no items"}
+			},
+			want: func(s *v1alpha.WorkflowStatus) {
+				s.FanOut["a"] = v1alpha.FanOutStatus{Total: 2, Failed: 1, Running: 1}
+				s.FanOut["b"] = v1alpha.FanOutStatus{Total: 3, Succeeded: 1, Running: 2}
+				s.FanOut["c"] = v1alpha.FanOutStatus{Message: "no items"}
+			},
+		},
+		{
+			name: "conditions",
+			concurrent: func(s *v1alpha.WorkflowStatus) {
+				s.Conditions[1].Status = corev1.ConditionTrue
+				s.Conditions[1].Reason = v1alpha.ReasonSuspended
+			},
+			modify: func(s *v1alpha.WorkflowStatus) {
+				s.Status = v1alpha.WorkflowFailed
+				s.Conditions = append(s.Conditions, v1alpha.WorkflowCondition{
+					Type: v1alpha.WorkflowCompleted, Status: corev1.ConditionTrue, Reason: "Failed",
+				})
+			},
+			want: func(s *v1alpha.WorkflowStatus) {
+				s.Status = v1alpha.WorkflowFailed
+				s.Conditions[1].Status = corev1.ConditionTrue
+				s.Conditions[1].Reason = v1alpha.ReasonSuspended
+				s.Conditions = append(s.Conditions, v1alpha.WorkflowCondition{
+					Type: v1alpha.WorkflowCompleted, Status: corev1.ConditionTrue, Reason: "Failed",
+				})
+			},
+		},
+		{
+			name: "first entries",
+			concurrent: func(s *v1alpha.WorkflowStatus) {
+				s.Status = v1alpha.WorkflowPending
+				s.JobStatus, s.JobBatch, s.FanOut, s.Conditions = nil, nil, nil, nil
+			},
+			modify: func(s *v1alpha.WorkflowStatus) {
+				s.FanOut["c"] = v1alpha.FanOutStatus{Total: 1, Running: 1}
+				s.JobStatus["c"] = v1alpha.JobWorking
+				s.JobBatch["c"] = &v1alpha.BatchReference{Kind: "Job", Name: "wf-c"}
+			},
+			want: func(s *v1alpha.WorkflowStatus) {
+				s.Status = v1alpha.WorkflowPending
+				s.JobStatus = map[string]v1alpha.JobPhase{"c": v1alpha.JobWorking}
+				s.JobBatch = map[string]*v1alpha.BatchReference{"c": {Kind: "Job", Name: "wf-c"}}
+				s.FanOut = map[string]v1alpha.FanOutStatus{"c": {Total: 1, Running: 1}}
+				s.Conditions = nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig, latest, modified, want := base(), base(), base(), base()
+			tt.concurrent(latest)
+			tt.modify(modified)
+			tt.want(want)
+			mergeStatus(latest, orig, modified)
+			for i := 0; i < len(latest.Conditions) && i < len(want.Conditions); i++ {
+				// The transition times are set to now.
+				latest.Conditions[i].LastTransitionTime = want.Conditions[i].LastTransitionTime
+			}
+			if !reflect.DeepEqual(latest, want) {
+				t.Errorf("mergeStatus() = %+v, want %+v", latest, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// placeholderPattern matches every {{...}} substitution of job data.
+var placeholderPattern = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+// generateNameSuffix stands for the random suffix the API server appends to
+// the generateName of a workflow.
+const generateNameSuffix = "xxxxx"
+
+// ValidateWorkflow returns the problems of wf that would make HandlePendingWf
+// reject it, or make the creation of its batch Jobs fail. A workflow created
+// from a template is only checked once the template has been applied.
+func ValidateWorkflow(wf *v1alpha.Workflow) field.ErrorList {
+	var errs field.ErrorList
+	_, templateApplied := wf.GetAnnotations()[v1alpha.WorkflowTemplateAnnotation]
+	if wf.Spec.WorkflowTemplateRef != nil && !templateApplied {
+		if len(wf.Inputs.Jobs) > 0 {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "workflowTemplateRef"), "may not be set together with inputs.jobs"))
+		}
+		if wf.Spec.WorkflowTemplateRef.Name == "" {
+			errs = append(errs, field.Required(field.NewPath("spec", "workflowTemplateRef", "name"), ""))
+		}
+		return errs
+	}
+
+	paramsPath := field.NewPath("spec", "arguments", "parameters")
+	declared := make(map[string]bool, len(wf.Spec.Arguments.Parameters))
+	for i, param := range wf.Spec.Arguments.Parameters {
+		switch {
+		case param.Name == "":
+			errs = append(errs, field.Required(paramsPath.Index(i).Child("name"), ""))
+		case declared[param.Name]:
+			errs = append(errs, field.Duplicate(paramsPath.Index(i).Child("name"), param.Name))
+		}
+		declared[param.Name] = true
+		if param.Value == nil && param.Default == nil {
+			errs = append(errs, field.Required(paramsPath.Index(i).Child("value"), "parameter has no default"))
+		}
+	}
+	if len(errs) == 0 {
+		if _, err := ResolveParameters(wf); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("inputs", "jobs"), "", err.Error()))
+		}
+	}
+
+	prefix := wf.Name
+	if prefix == "" {
+		prefix = wf.GenerateName + generateNameSuffix
+	}
+	errs = append(errs, validateRetryStrategy(wf.Inputs.RetryStrategy, field.NewPath("inputs", "retryStrategy"))...)
+	jobsPath := field.NewPath("inputs", "jobs")
+	jobs := WorkflowJobs(wf)
+	names := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		names[job.Name] = true
+	}
+	seen := make(map[string]bool, len(jobs))
+	for i, job := range jobs {
+		path := jobsPath.Index(i)
+		switch {
+		case job.Name == "":
+			errs = append(errs, field.Required(path.Child("name"), ""))
+		case seen[job.Name]:
+			errs = append(errs, field.Duplicate(path.Child("name"), job.Name))
+		default:
+			batchName := longestBatchName(prefix+"-"+job.Name, job, wf)
+			for _, msg := range validation.IsDNS1123Label(batchName) {
+				errs = append(errs, field.Invalid(path.Child("name"), job.Name, fmt.Sprintf("batch Job name %s: %s", batchName, msg)))
+			}
+		}
+		seen[job.Name] = true
+		if job.Type == "" {
+			errs = append(errs, field.Required(path.Child("type"), ""))
+		}
+		if job.Data != "" && !json.Valid([]byte(placeholderPattern.ReplaceAllString(job.Data, "0"))) {
+			errs = append(errs, field.Invalid(path.Child("data"), job.Data, "must be valid JSON"))
+		}
+		errs = append(errs, validateRetryStrategy(job.RetryStrategy, path.Child("retryStrategy"))...)
+		for j, dep := range job.DependsOn {
+			if !names[dep] {
+				errs = append(errs, field.NotFound(path.Child("dependsOn").Index(j), dep))
+			}
+		}
+	}
+	if len(errs) == 0 {
+		if _, err := SortJobs(jobs); err != nil {
+			errs = append(errs, field.Invalid(jobsPath, "", err.Error()))
+		} else if err := validateAttemptNames(jobs, wf.Inputs.RetryStrategy); err != nil {
+			errs = append(errs, field.Invalid(jobsPath, "", err.Error()))
+		}
+	}
+	return errs
+}
+
+// validateRetryStrategy checks the backoff durations of strategy, which
+// retryDelay would otherwise ignore.
+func validateRetryStrategy(strategy *v1alpha.RetryStrategy, path *field.Path) field.ErrorList {
+	if strategy == nil || strategy.Backoff == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	backoffPath := path.Child("backoff")
+	for _, d := range []struct {
+		name  string
+		value string
+	}{
+		{"duration", strategy.Backoff.Duration},
+		{"maxDuration", strategy.Backoff.MaxDuration},
+	} {
+		if d.value == "" {
+			continue
+		}
+		if duration, err := time.ParseDuration(d.value); err != nil {
+			errs = append(errs, field.Invalid(backoffPath.Child(d.name), d.value, "must be a duration such as 30s or 5m"))
+		} else if duration < 0 {
+			errs = append(errs, field.Invalid(backoffPath.Child(d.name), d.value, "must not be negative"))
+		}
+	}
+	return errs
+}
+
+// longestBatchName returns the longest batch Job name a job may get, taking
+// fan-out children and retries into account.
+func longestBatchName(batchName string, job v1alpha.Job, wf *v1alpha.Workflow) string {
+	if n := len(job.WithItems); n > 0 {
+		batchName = childName(batchName, n-1)
+	}
+	strategy := job.RetryStrategy
+	if strategy == nil {
+		strategy = wf.Inputs.RetryStrategy
+	}
+	if strategy != nil {
+		batchName = attemptName(batchName, int(strategy.Limit))
+	}
+	return batchName
+}
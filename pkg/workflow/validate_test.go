@@ -0,0 +1,218 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateWorkflow(t *testing.T) {
+	valid := func() *v1alpha.Workflow {
+		return &v1alpha.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "wf"},
+			Spec: v1alpha.WorkflowSpec{Arguments: v1alpha.Arguments{Parameters: []v1alpha.Parameter{
+				{Name: "scene", Default: strPtr("s1")},
+			}}},
+			Inputs: v1alpha.WorkflowInputs{Jobs: []v1alpha.Job{
+				{Name: "import", Type: "import", Data: `{"scene":"{{workflow.parameters.scene}}"}`},
+				{Name: "render", Type: "render", DependsOn: []string{"import"}, Data: `{"id":{{jobs.import.outputs.assetId}}}`},
+			}},
+		}
+	}
+	items := []runtime.RawExtension{{Raw: []byte(`1`)}, {Raw: []byte(`2`)}}
+	tests := []struct {
+		name   string
+		change func(wf *v1alpha.Workflow)
+		want   []string
+	}{
+		{name: "valid", change: func(wf *v1alpha.Workflow) {}},
+		{
+			name: "generateName",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Name, wf.GenerateName = "", "wf-"
+			},
+		},
+		{
+			name: "template not applied",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Spec.WorkflowTemplateRef = &v1alpha.WorkflowTemplateRef{Name: "render"}
+				wf.Inputs.Jobs = nil
+			},
+		},
+		{
+			name: "template and jobs",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Spec.WorkflowTemplateRef = &v1alpha.WorkflowTemplateRef{}
+			},
+			want: []string{"spec.workflowTemplateRef", "spec.workflowTemplateRef.name"},
+		},
+		{
+			name: "template applied",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Spec.WorkflowTemplateRef = &v1alpha.WorkflowTemplateRef{Name: "render"}
+				wf.Annotations = map[string]string{v1alpha.WorkflowTemplateAnnotation: "42"}
+				wf.Inputs.Jobs[1].Type = ""
+			},
+			want: []string{"inputs.jobs[1].type"},
+		},
+		{
+			name: "parameters",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Spec.Arguments.Parameters = append(wf.Spec.Arguments.Parameters,
+					v1alpha.Parameter{Value: strPtr("x")},
+					v1alpha.Parameter{Name: "scene", Value: strPtr("s2")},
+					v1alpha.Parameter{Name: "quality"},
+				)
+			},
+			want: []string{"spec.arguments.parameters[1].name", "spec.arguments.parameters[2].name", "spec.arguments.parameters[3].value"},
+		},
+		{
+			name: "undeclared parameter",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Spec.Arguments.Parameters = nil
+			},
+			want: []string{"inputs.jobs"},
+		},
+		{
+			name: "jobs",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Inputs.Jobs = append(wf.Inputs.Jobs,
+					v1alpha.Job{Type: "render"},
+					v1alpha.Job{Name: "render", Type: "render"},
+					v1alpha.Job{Name: "export", Data: `{"id":`, DependsOn: []string{"publish"}},
+				)
+			},
+			want: []string{
+				"inputs.jobs[2].name",
+				"inputs.jobs[3].name",
+				"inputs.jobs[4].type",
+				"inputs.jobs[4].data",
+				"inputs.jobs[4].dependsOn[0]",
+			},
+		},
+		{
+			name: "cycle",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Inputs.Jobs[0].DependsOn = []string{"render"}
+			},
+			want: []string{"inputs.jobs"},
+		},
+		{
+			name: "invalid fan-out",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Inputs.Jobs[1].WithItems = items
+				wf.Inputs.Jobs[1].WithParam = "import"
+			},
+			want: []string{"inputs.jobs"},
+		},
+		{
+			name: "name of a retry",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Inputs.RetryStrategy = &v1alpha.RetryStrategy{Limit: 1}
+				wf.Inputs.Jobs[1].Name = "import-1"
+			},
+			want: []string{"inputs.jobs"},
+		},
+		{
+			name: "invalid name",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Inputs.Jobs[0].Name = "Import"
+				wf.Inputs.Jobs[1].DependsOn = []string{"Import"}
+			},
+			want: []string{"inputs.jobs[0].name"},
+		},
+		{
+			name: "longest name",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Inputs.Jobs[0].Name = strings.Repeat("i", 60)
+				wf.Inputs.Jobs[1].Data = ""
+				wf.Inputs.Jobs[1].DependsOn = nil
+			},
+		},
+		{
+			name: "name too long with retries",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Inputs.Jobs[0].Name = strings.Repeat("i", 60)
+				wf.Inputs.Jobs[1].Data = ""
+				wf.Inputs.Jobs[1].DependsOn = nil
+				wf.Inputs.RetryStrategy = &v1alpha.RetryStrategy{Limit: 1}
+			},
+			want: []string{"inputs.jobs[0].name"},
+		},
+		{
+			name: "name too long with fan-out children",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Inputs.Jobs[1].Name = strings.Repeat("r", 59)
+				wf.Inputs.Jobs[1].WithItems = items
+			},
+			want: []string{"inputs.jobs[1].name"},
+		},
+		{
+			name: "name too long with generateName",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Name, wf.GenerateName = "", "wf-"
+				wf.Inputs.Jobs[1].Name = strings.Repeat("r", 55)
+			},
+			want: []string{"inputs.jobs[1].name"},
+		},
+		{
+			name: "backoff",
+			change: func(wf *v1alpha.Workflow) {
+				wf.Inputs.RetryStrategy = &v1alpha.RetryStrategy{Backoff: &v1alpha.Backoff{Duration: "10s", MaxDuration: "1h30"}}
+				wf.Inputs.Jobs[1].RetryStrategy = &v1alpha.RetryStrategy{Backoff: &v1alpha.Backoff{Duration: "10"}}
+			},
+			want: []string{"inputs.retryStrategy.backoff.maxDuration", "inputs.jobs[1].retryStrategy.backoff.duration"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := valid()
+			tt.change(wf)
+			errs := ValidateWorkflow(wf)
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Field)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("ValidateWorkflow() = %v, want errors for %v", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRetryStrategy(t *testing.T) {
+	path := field.NewPath("inputs", "retryStrategy")
+	tests := []struct {
+		name     string
+		strategy *v1alpha.RetryStrategy
+		want     []string
+	}{
+		{name: "unset"},
+		{name: "no backoff", strategy: &v1alpha.RetryStrategy{Limit: 2}},
+		{name: "valid", strategy: &v1alpha.RetryStrategy{Limit: 2, Backoff: &v1alpha.Backoff{Duration: "30s", Factor: 2, MaxDuration: "5m"}}},
+		{name: "no unit", strategy: &v1alpha.RetryStrategy{Backoff: &v1alpha.Backoff{Duration: "30"}}, want: []string{"inputs.retryStrategy.backoff.duration"}},
+		{name: "negative", strategy: &v1alpha.RetryStrategy{Backoff: &v1alpha.Backoff{Duration: "-1m"}}, want: []string{"inputs.retryStrategy.backoff.duration"}},
+		{
+			name:     "both invalid",
+			strategy: &v1alpha.RetryStrategy{Backoff: &v1alpha.Backoff{Duration: "soon", MaxDuration: "1 hour"}},
+			want:     []string{"inputs.retryStrategy.backoff.duration", "inputs.retryStrategy.backoff.maxDuration"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateRetryStrategy(tt.strategy, path)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("validateRetryStrategy() = %v, want errors for %v", errs, tt.want)
+			}
+			for i, err := range errs {
+				if err.Field != tt.want[i] {
+					t.Errorf("error %d is for %s, want %s", i, err.Field, tt.want[i])
+				}
+			}
+		})
+	}
+}
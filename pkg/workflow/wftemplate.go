@@ -0,0 +1,119 @@
+package operator
+
+import (
+	"fmt"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// invalidTemplateError is returned when a workflow cannot be created from the
+// template it refers to. Other errors are transient.
+type invalidTemplateError struct {
+	message string
+}
+
+func (e *invalidTemplateError) Error() string {
+	return e.message
+}
+
+func (w *WorkflowOp) GetWorkflowTemplate(name, namespace string) (*v1alpha.WorkflowTemplate, error) {
+	tmpl := &v1alpha.WorkflowTemplate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "WorkflowTemplate",
+			APIVersion: "threekit.com/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	err := w.provider.Get(tmpl)
+	return tmpl, err
+}
+
+// applyWorkflowTemplate copies the inputs of the template wf refers to into
+// wf and merges the template arguments with its own, once. The template's
+// resourceVersion is kept in WorkflowTemplateAnnotation so that the workflow
+// keeps running the graph it was admitted with when the template changes. wf
+// is updated with the result from the server.
+func (w *WorkflowOp) applyWorkflowTemplate(wf *v1alpha.Workflow) error {
+	ref := wf.Spec.WorkflowTemplateRef
+	if ref == nil {
+		return nil
+	}
+	if _, found := wf.GetAnnotations()[v1alpha.WorkflowTemplateAnnotation]; found {
+		return nil
+	}
+	if len(wf.Inputs.Jobs) > 0 {
+		return &invalidTemplateError{"workflow sets both jobs and workflowTemplateRef"}
+	}
+	tmpl, err := w.GetWorkflowTemplate(ref.Name, wf.Namespace)
+	if kubeerr.IsNotFound(err) {
+		return &invalidTemplateError{fmt.Sprintf("workflow template %s not found", ref.Name)}
+	}
+	if err != nil {
+		return err
+	}
+	err = w.updateWorkflowObject(wf, func(wf *v1alpha.Workflow) {
+		wf.Inputs = *tmpl.Spec.Inputs.DeepCopy()
+		// The workflow is updated, not created: the admission webhook does
+		// not normalize the job names of the template.
+		v1alpha.NormalizeJobNames(&wf.Inputs)
+		wf.Spec.Arguments = mergeArguments(tmpl.Spec.Arguments, wf.Spec.Arguments)
+		annotations := wf.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[v1alpha.WorkflowTemplateAnnotation] = tmpl.ResourceVersion
+		wf.SetAnnotations(annotations)
+	})
+	if kubeerr.IsInvalid(err) || kubeerr.IsForbidden(err) {
+		// The validating webhook denied the workflow with the template
+		// jobs and arguments: retrying would fail the same way.
+		return &invalidTemplateError{fmt.Sprintf("workflow from template %s is invalid: %v", ref.Name, err)}
+	}
+	return err
+}
+
+// mergeArguments returns the parameters of the template with the values given
+// by the workflow, followed by the workflow parameters the template does not
+// declare.
+func mergeArguments(tmpl, wf v1alpha.Arguments) v1alpha.Arguments {
+	values := make(map[string]v1alpha.Parameter, len(wf.Parameters))
+	for _, param := range wf.Parameters {
+		values[param.Name] = param
+	}
+	var merged v1alpha.Arguments
+	for _, param := range tmpl.Parameters {
+		if given, found := values[param.Name]; found {
+			delete(values, param.Name)
+			if given.Value != nil {
+				param.Value = given.Value
+			}
+			if given.Default != nil {
+				param.Default = given.Default
+			}
+		}
+		merged.Parameters = append(merged.Parameters, param)
+	}
+	for _, param := range wf.Parameters {
+		if _, found := values[param.Name]; found {
+			merged.Parameters = append(merged.Parameters, param)
+		}
+	}
+	return merged
+}
+
+// workflowTemplateStatus returns the template wf was created from, if any.
+func workflowTemplateStatus(wf *v1alpha.Workflow) *v1alpha.WorkflowTemplateStatus {
+	version, found := wf.GetAnnotations()[v1alpha.WorkflowTemplateAnnotation]
+	if wf.Spec.WorkflowTemplateRef == nil || !found {
+		return nil
+	}
+	return &v1alpha.WorkflowTemplateStatus{
+		Name:            wf.Spec.WorkflowTemplateRef.Name,
+		ResourceVersion: version,
+	}
+}
@@ -0,0 +1,190 @@
+package operator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/kube"
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// templateProvider serves workflow templates and records workflow updates.
+// The other Provider methods are not implemented.
+type templateProvider struct {
+	kube.Provider
+	templates map[string]*v1alpha.WorkflowTemplate
+	updated   []*v1alpha.Workflow
+	// updateErr, when set, is returned by Update, as when the admission
+	// webhook denies the update.
+	updateErr error
+}
+
+func (p *templateProvider) Get(object runtime.Object) error {
+	tmpl := object.(*v1alpha.WorkflowTemplate)
+	stored, found := p.templates[tmpl.Namespace+"/"+tmpl.Name]
+	if !found {
+		return kubeerr.NewNotFound(schema.GroupResource{Group: "threekit.com", Resource: "workflowtemplates"}, tmpl.Name)
+	}
+	stored.DeepCopyInto(tmpl)
+	return nil
+}
+
+func (p *templateProvider) Update(object runtime.Object) error {
+	if p.updateErr != nil {
+		return p.updateErr
+	}
+	p.updated = append(p.updated, object.(*v1alpha.Workflow).DeepCopy())
+	return nil
+}
+
+func TestMergeArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl []v1alpha.Parameter
+		wf   []v1alpha.Parameter
+		want []v1alpha.Parameter
+	}{
+		{name: "none"},
+		{
+			name: "template defaults",
+			tmpl: []v1alpha.Parameter{{Name: "scene", Default: strPtr("s0")}},
+			want: []v1alpha.Parameter{{Name: "scene", Default: strPtr("s0")}},
+		},
+		{
+			name: "workflow values",
+			tmpl: []v1alpha.Parameter{{Name: "scene", Default: strPtr("s0")}, {Name: "quality", Value: strPtr("low")}},
+			wf:   []v1alpha.Parameter{{Name: "quality", Value: strPtr("high")}, {Name: "scene", Value: strPtr("s1")}},
+			want: []v1alpha.Parameter{{Name: "scene", Value: strPtr("s1"), Default: strPtr("s0")}, {Name: "quality", Value: strPtr("high")}},
+		},
+		{
+			name: "workflow default",
+			tmpl: []v1alpha.Parameter{{Name: "scene", Default: strPtr("s0")}},
+			wf:   []v1alpha.Parameter{{Name: "scene", Default: strPtr("s2")}},
+			want: []v1alpha.Parameter{{Name: "scene", Default: strPtr("s2")}},
+		},
+		{
+			name: "undeclared parameters last",
+			tmpl: []v1alpha.Parameter{{Name: "scene", Default: strPtr("s0")}},
+			wf:   []v1alpha.Parameter{{Name: "extra", Value: strPtr("x")}, {Name: "scene", Value: strPtr("s1")}},
+			want: []v1alpha.Parameter{{Name: "scene", Value: strPtr("s1"), Default: strPtr("s0")}, {Name: "extra", Value: strPtr("x")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeArguments(v1alpha.Arguments{Parameters: tt.tmpl}, v1alpha.Arguments{Parameters: tt.wf})
+			if formatParameters(got.Parameters) != formatParameters(tt.want) {
+				t.Errorf("mergeArguments() = %s, want %s", formatParameters(got.Parameters), formatParameters(tt.want))
+			}
+		})
+	}
+}
+
+func formatParameters(params []v1alpha.Parameter) string {
+	s := ""
+	for _, param := range params {
+		value, def := "<nil>", "<nil>"
+		if param.Value != nil {
+			value = *param.Value
+		}
+		if param.Default != nil {
+			def = *param.Default
+		}
+		s += fmt.Sprintf("%s=%s(%s) ", param.Name, value, def)
+	}
+	return s
+}
+
+func TestApplyWorkflowTemplate(t *testing.T) {
+	tmpl := &v1alpha.WorkflowTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "render", Namespace: "ns", ResourceVersion: "42"},
+		Spec: v1alpha.WorkflowTemplateSpec{
+			Inputs: v1alpha.WorkflowInputs{Jobs: []v1alpha.Job{
+				{Name: "Import Scene", Type: "import"},
+				{Name: "render_scene", Type: "render", DependsOn: []string{"Import Scene"}},
+			}},
+			Arguments: v1alpha.Arguments{Parameters: []v1alpha.Parameter{{Name: "scene", Default: strPtr("s0")}}},
+		},
+	}
+	workflow := func(annotations map[string]string, jobs ...v1alpha.Job) *v1alpha.Workflow {
+		return &v1alpha.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "wf", Namespace: "ns", Annotations: annotations},
+			Spec: v1alpha.WorkflowSpec{
+				WorkflowTemplateRef: &v1alpha.WorkflowTemplateRef{Name: "render"},
+				Arguments:           v1alpha.Arguments{Parameters: []v1alpha.Parameter{{Name: "scene", Value: strPtr("s1")}}},
+			},
+			Inputs: v1alpha.WorkflowInputs{Jobs: jobs},
+		}
+	}
+	missing := workflow(nil)
+	missing.Spec.WorkflowTemplateRef.Name = "missing"
+	denied := kubeerr.NewInvalid(schema.GroupKind{Group: "threekit.com", Kind: "Workflow"}, "wf", nil)
+	tests := []struct {
+		name        string
+		wf          *v1alpha.Workflow
+		updateErr   error
+		wantUpdate  bool
+		wantInvalid bool
+		wantErr     bool
+	}{
+		{name: "no template", wf: &v1alpha.Workflow{ObjectMeta: metav1.ObjectMeta{Name: "wf", Namespace: "ns"}}},
+		{name: "applied", wf: workflow(map[string]string{v1alpha.WorkflowTemplateAnnotation: "41"})},
+		{name: "jobs and template", wf: workflow(nil, v1alpha.Job{Name: "import", Type: "import"}), wantInvalid: true},
+		{name: "missing template", wf: missing, wantInvalid: true},
+		{name: "apply", wf: workflow(nil), wantUpdate: true},
+		{name: "denied", wf: workflow(nil), updateErr: denied, wantInvalid: true},
+		{name: "forbidden", wf: workflow(nil), updateErr: kubeerr.NewForbidden(schema.GroupResource{Group: "threekit.com", Resource: "workflows"}, "wf", nil), wantInvalid: true},
+		{name: "update failed", wf: workflow(nil), updateErr: kubeerr.NewServiceUnavailable("etcd"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &templateProvider{templates: map[string]*v1alpha.WorkflowTemplate{"ns/render": tmpl.DeepCopy()}, updateErr: tt.updateErr}
+			w := &WorkflowOp{provider: p}
+			err := w.applyWorkflowTemplate(tt.wf)
+			if _, invalid := err.(*invalidTemplateError); invalid != tt.wantInvalid || (err != nil && !invalid && !tt.wantErr) {
+				t.Fatalf("applyWorkflowTemplate() error = %v, want invalid %v", err, tt.wantInvalid)
+			}
+			if tt.wantErr && err == nil {
+				t.Fatal("applyWorkflowTemplate() succeeded, want an error")
+			}
+			if !tt.wantUpdate {
+				if len(p.updated) != 0 {
+					t.Errorf("applyWorkflowTemplate() updated the workflow %d times, want none", len(p.updated))
+				}
+				return
+			}
+			if len(p.updated) != 1 {
+				t.Fatalf("applyWorkflowTemplate() updated the workflow %d times, want once", len(p.updated))
+			}
+			wf := p.updated[0]
+			if got := wf.Annotations[v1alpha.WorkflowTemplateAnnotation]; got != "42" {
+				t.Errorf("template annotation = %q, want 42", got)
+			}
+			jobs := wf.Inputs.Jobs
+			if len(jobs) != 2 || jobs[0].Name != "import-scene" || jobs[1].Name != "render-scene" || jobs[1].DependsOn[0] != "import-scene" {
+				t.Errorf("jobs = %+v, want the template jobs with normalized names", jobs)
+			}
+			if want := "scene=s1(s0) "; formatParameters(wf.Spec.Arguments.Parameters) != want {
+				t.Errorf("arguments = %s, want %s", formatParameters(wf.Spec.Arguments.Parameters), want)
+			}
+			if stored := p.templates["ns/render"].Spec.Inputs.Jobs[0].Name; stored != "Import Scene" {
+				t.Errorf("the template job was renamed to %s", stored)
+			}
+		})
+	}
+}
+
+func TestWorkflowTemplateStatus(t *testing.T) {
+	wf := &v1alpha.Workflow{Spec: v1alpha.WorkflowSpec{WorkflowTemplateRef: &v1alpha.WorkflowTemplateRef{Name: "render"}}}
+	if status := workflowTemplateStatus(wf); status != nil {
+		t.Errorf("workflowTemplateStatus() = %+v before the template is applied, want nil", status)
+	}
+	wf.Annotations = map[string]string{v1alpha.WorkflowTemplateAnnotation: "42"}
+	want := v1alpha.WorkflowTemplateStatus{Name: "render", ResourceVersion: "42"}
+	if status := workflowTemplateStatus(wf); status == nil || *status != want {
+		t.Errorf("workflowTemplateStatus() = %+v, want %+v", status, want)
+	}
+}
@@ -0,0 +1,327 @@
+package operator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+)
+
+// A when expression compares references and literals:
+//
+//	jobs.import.status == "ok" && jobs.import.outputs.assetId != ""
+//
+// References are jobs.<name>.status, jobs.<name>.outputs.<field> and
+// workflow.parameters.<name>. Literals are quoted strings, numbers, true and
+// false. Operands compare as numbers when both are numbers and as strings
+// otherwise. Conditions combine with !, && and || and group with parentheses.
+// A missing output resolves to "".
+
+type whenExpr interface {
+	eval(resolve func(ref string) (string, error)) (string, error)
+	refs() []string
+}
+
+type whenLiteral string
+
+type whenRef string
+
+type whenNot struct {
+	operand whenExpr
+}
+
+type whenBinary struct {
+	op          string
+	left, right whenExpr
+}
+
+func (l whenLiteral) eval(func(string) (string, error)) (string, error) {
+	return string(l), nil
+}
+
+func (l whenLiteral) refs() []string { return nil }
+
+func (r whenRef) eval(resolve func(string) (string, error)) (string, error) {
+	return resolve(string(r))
+}
+
+func (r whenRef) refs() []string { return []string{string(r)} }
+
+func (n whenNot) eval(resolve func(string) (string, error)) (string, error) {
+	value, err := evalBool(n.operand, resolve)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(!value), nil
+}
+
+func (n whenNot) refs() []string { return n.operand.refs() }
+
+func (b whenBinary) eval(resolve func(string) (string, error)) (string, error) {
+	switch b.op {
+	case "&&", "||":
+		left, err := evalBool(b.left, resolve)
+		if err != nil {
+			return "", err
+		}
+		if left == (b.op == "||") {
+			return strconv.FormatBool(left), nil
+		}
+		right, err := evalBool(b.right, resolve)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(right), nil
+	}
+	left, err := b.left.eval(resolve)
+	if err != nil {
+		return "", err
+	}
+	right, err := b.right.eval(resolve)
+	if err != nil {
+		return "", err
+	}
+	cmp := strings.Compare(left, right)
+	if l, err := strconv.ParseFloat(left, 64); err == nil {
+		if r, err := strconv.ParseFloat(right, 64); err == nil {
+			switch {
+			case l < r:
+				cmp = -1
+			case l > r:
+				cmp = 1
+			default:
+				cmp = 0
+			}
+		}
+	}
+	var result bool
+	switch b.op {
+	case "==":
+		result = cmp == 0
+	case "!=":
+		result = cmp != 0
+	case "<":
+		result = cmp < 0
+	case "<=":
+		result = cmp <= 0
+	case ">":
+		result = cmp > 0
+	case ">=":
+		result = cmp >= 0
+	}
+	return strconv.FormatBool(result), nil
+}
+
+func (b whenBinary) refs() []string { return append(b.left.refs(), b.right.refs()...) }
+
+func evalBool(expr whenExpr, resolve func(string) (string, error)) (bool, error) {
+	value, err := expr.eval(resolve)
+	if err != nil {
+		return false, err
+	}
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not a boolean", value)
+}
+
+type whenParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseWhen parses a when expression.
+func parseWhen(expr string) (whenExpr, error) {
+	tokens, err := tokenizeWhen(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &whenParser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s", p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+func (p *whenParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *whenParser) parseOr() (whenExpr, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.peek() == "||" {
+		p.pos++
+		var right whenExpr
+		right, err = p.parseAnd()
+		left = whenBinary{op: "||", left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *whenParser) parseAnd() (whenExpr, error) {
+	left, err := p.parseComparison()
+	for err == nil && p.peek() == "&&" {
+		p.pos++
+		var right whenExpr
+		right, err = p.parseComparison()
+		left = whenBinary{op: "&&", left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *whenParser) parseComparison() (whenExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return whenBinary{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseUnary() (whenExpr, error) {
+	token := p.peek()
+	p.pos++
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == "!":
+		operand, err := p.parseUnary()
+		return whenNot{operand: operand}, err
+	case token == "(":
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return e, nil
+	case token[0] == '"' || token[0] == '\'':
+		return whenLiteral(token[1 : len(token)-1]), nil
+	case token == "true" || token == "false":
+		return whenLiteral(token), nil
+	}
+	if _, err := strconv.ParseFloat(token, 64); err == nil {
+		return whenLiteral(token), nil
+	}
+	if strings.HasPrefix(token, "jobs.") || strings.HasPrefix(token, "workflow.") {
+		return whenRef(token), nil
+	}
+	return nil, fmt.Errorf("unexpected %s", token)
+}
+
+func tokenizeWhen(expr string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, expr[i:i+end+2])
+			i += end + 2
+		case strings.HasPrefix(expr[i:], "&&"), strings.HasPrefix(expr[i:], "||"),
+			strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="),
+			strings.HasPrefix(expr[i:], "<="), strings.HasPrefix(expr[i:], ">="):
+			tokens = append(tokens, expr[i:i+2])
+			i += 2
+		case strings.IndexByte("!()<>", c) >= 0:
+			tokens = append(tokens, expr[i:i+1])
+			i++
+		default:
+			start := i
+			for i < len(expr) && isWhenIdent(rune(expr[i])) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			tokens = append(tokens, expr[start:i])
+		}
+	}
+	return tokens, nil
+}
+
+func isWhenIdent(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-'
+}
+
+// whenJobReferences returns the names of the jobs an expression refers to.
+func whenJobReferences(expr whenExpr) []string {
+	var jobs []string
+	for _, ref := range expr.refs() {
+		if parts := strings.SplitN(ref, ".", 3); len(parts) == 3 && parts[0] == "jobs" {
+			jobs = append(jobs, parts[1])
+		}
+	}
+	return jobs
+}
+
+// evalWhen evaluates the when expression of job against the status of wf.
+func evalWhen(job v1alpha.Job, wf *v1alpha.Workflow) (bool, error) {
+	expr, err := parseWhen(job.When)
+	if err != nil {
+		return false, err
+	}
+	return evalBool(expr, func(ref string) (string, error) {
+		return resolveWhenRef(ref, wf)
+	})
+}
+
+func resolveWhenRef(ref string, wf *v1alpha.Workflow) (string, error) {
+	parts := strings.Split(ref, ".")
+	if len(parts) >= 3 && parts[0] == "jobs" {
+		name := parts[1]
+		switch {
+		case len(parts) == 3 && parts[2] == "status":
+			return string(wf.Status.JobStatus[name]), nil
+		case len(parts) == 4 && parts[2] == "outputs":
+			batch := wf.Status.JobBatch[name]
+			if batch == nil {
+				return "", nil
+			}
+			if value, found := batch.Outputs[parts[3]]; found {
+				return value, nil
+			}
+			if parts[3] == ResultOutput {
+				return batch.Result, nil
+			}
+			return "", nil
+		}
+	}
+	if len(parts) == 3 && parts[0] == "workflow" && parts[1] == "parameters" {
+		params, err := ResolveParameters(wf)
+		if err != nil {
+			return "", err
+		}
+		if value, found := params[parts[2]]; found {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("unknown reference %s", ref)
+}
@@ -0,0 +1,97 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+)
+
+func TestParseWhenErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{``, "unexpected end of expression"},
+		{`jobs.a.status ==`, "unexpected end of expression"},
+		{`(jobs.a.status == "ok"`, "missing )"},
+		{`jobs.a.status == "ok")`, "unexpected )"},
+		{`jobs.a.status == "ok`, "unterminated string"},
+		{`status == "ok"`, "unexpected status"},
+		{`jobs.a.status = "ok"`, `unexpected '='`},
+		{`jobs.a.status == "ok" "failed"`, `unexpected "failed"`},
+	}
+	for _, tt := range tests {
+		if _, err := parseWhen(tt.expr); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseWhen(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEvalWhen(t *testing.T) {
+	value := "high"
+	wf := &v1alpha.Workflow{
+		Spec: v1alpha.WorkflowSpec{Arguments: v1alpha.Arguments{Parameters: []v1alpha.Parameter{
+			{Name: "quality", Value: &value},
+		}}},
+		Status: v1alpha.WorkflowStatus{
+			JobStatus: map[string]v1alpha.JobPhase{
+				"import": v1alpha.JobOK,
+				"scan":   v1alpha.JobFailed,
+			},
+			JobBatch: map[string]*v1alpha.BatchReference{
+				"import": {Result: `{"count":12}`, Outputs: map[string]string{"assetId": "a1", "count": "12"}},
+				"scan":   {Result: "9"},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "status", expr: `jobs.import.status == "ok"`, want: true},
+		{name: "single quotes", expr: `jobs.scan.status != 'ok'`, want: true},
+		{name: "output", expr: `jobs.import.outputs.assetId == "a1"`, want: true},
+		{name: "missing output", expr: `jobs.import.outputs.missing == ""`, want: true},
+		{name: "whole result", expr: `jobs.scan.outputs.result == 9`, want: true},
+		{name: "numbers", expr: `jobs.import.outputs.count > 9`, want: true},
+		{name: "strings", expr: `jobs.import.outputs.assetId < "a10"`, want: true},
+		{name: "number and string", expr: `jobs.import.outputs.count < "9a"`, want: true},
+		{name: "equal numbers", expr: `jobs.import.outputs.count == 12.0`, want: true},
+		{name: "parameter", expr: `workflow.parameters.quality == "high"`, want: true},
+		{name: "and", expr: `jobs.import.status == "ok" && jobs.scan.status == "ok"`, want: false},
+		{name: "or", expr: `jobs.scan.status == "ok" || jobs.import.status == "ok"`, want: true},
+		{name: "and before or", expr: `true || false && false`, want: true},
+		{name: "parentheses", expr: `(true || false) && false`, want: false},
+		{name: "not", expr: `!(jobs.scan.status == "ok")`, want: true},
+		{name: "literal", expr: `false`, want: false},
+		{name: "short circuit", expr: `false && jobs.export.missing`, want: false},
+		{name: "not a boolean", expr: `jobs.import.status`, wantErr: true},
+		{name: "unknown parameter", expr: `workflow.parameters.size == 1`, wantErr: true},
+		{name: "unknown reference", expr: `jobs.import.name == "import"`, wantErr: true},
+		{name: "invalid", expr: `jobs.import.status ==`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evalWhen(v1alpha.Job{Name: "export", When: tt.expr}, wf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evalWhen(%s) error = %v, want error %v", tt.expr, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evalWhen(%s) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhenJobReferences(t *testing.T) {
+	expr, err := parseWhen(`jobs.a.status == "ok" && (jobs.b-1.outputs.x > 2 || workflow.parameters.c == "d") && !jobs.e.status`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(whenJobReferences(expr), ","); got != "a,b-1,e" {
+		t.Errorf("whenJobReferences() = %s, want a,b-1,e", got)
+	}
+}
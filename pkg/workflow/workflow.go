@@ -1,196 +1,454 @@
 package operator
 
 import (
-	"bytes"
-	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/kube"
 	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	"github.com/Ziyang2go/workflowop/pkg/logsink"
+	"github.com/Ziyang2go/workflowop/pkg/quota"
+	template "github.com/Ziyang2go/workflowop/pkg/templates"
 	"github.com/sirupsen/logrus"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	kubeerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type WorkflowOpMethod interface {
 	HandleWorkflow(*v1alpha.Workflow) error
 	HandleJob(*batchv1.Job) error
+	HandleConfigMap(*corev1.ConfigMap) error
+	HandleCronWorkflow(*v1alpha.CronWorkflow) error
+	LabelJobs() error
 }
 
 type WorkflowOp struct {
-	provider kube.Provider
+	provider          kube.Provider
+	registry          *template.Registry
+	jobTypesConfigMap string
+	logTailBytes      int
+	logSink           logsink.Sink
+	history           HistoryStore
+	retention         Retention
+	quota             *quota.Scheduler
+	quotaConfigMap    string
+	jobs              *JobCache
 }
 
-func NewWorkflowOp(provider kube.Provider) WorkflowOpMethod {
-	return &WorkflowOp{
-		provider: provider,
+func NewWorkflowOp(provider kube.Provider, opts ...Option) WorkflowOpMethod {
+	w := &WorkflowOp{
+		provider:     provider,
+		registry:     template.NewRegistry(),
+		logTailBytes: DefaultLogTailBytes,
+		quota:        quota.NewScheduler(DefaultQuotaStaleAfter),
 	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
 }
 
 func (w *WorkflowOp) HandleWorkflow(o *v1alpha.Workflow) error {
+	o = o.DeepCopy()
+	v1alpha.SetObjectDefaults_Workflow(o)
+	if o.DeletionTimestamp != nil {
+		return w.HandleDeletingWf(o)
+	}
+	if o.Spec.Cancel && !o.Status.Status.Finished() {
+		return w.HandleTerminatingWf(o)
+	}
 	var err error
 	switch wfStatus := o.Status.Status; wfStatus {
-	case "", "pending":
+	case "", v1alpha.WorkflowPending:
 		err = w.HandlePendingWf(o)
-	case "working":
+	case v1alpha.WorkflowWorking:
 		err = w.HandleWorkingWf(o)
-	case "ok", "failed":
+	case v1alpha.WorkflowTerminating:
+		err = w.HandleTerminatingWf(o)
+	case v1alpha.WorkflowOK, v1alpha.WorkflowFailed, v1alpha.WorkflowCancelled:
 		err = w.CleanupWf(o)
 	default:
 		logrus.Errorf("unknown workflow status %s", wfStatus)
+		err = w.FailWorkflow(v1alpha.ReasonUnknownPhase, fmt.Sprintf("unknown workflow status %q", wfStatus), o, o.DeepCopy())
 	}
 	return err
 }
 
-func (w *WorkflowOp) HandlePendingWf(wf *v1alpha.Workflow) error {
-	jobs := wf.Inputs.Jobs
-	batches := wf.Spec.JobBatch
-	statuses := wf.Status.JobStatus
-	changed := false
-	var updateErr error
-	for _, job := range jobs {
-		name := job.Name
-		batchName := wf.GetObjectMeta().GetName() + "-" + name
-		if batches[name] != nil {
-			status := wf.Status.JobStatus[name]
-			logrus.Printf("%s job %s is in status %s", job.Type, name, status)
-			continue
-		}
-		if batches == nil {
-			batches = make(map[string]*v1alpha.BatchReference)
-		}
-		err := w.CreateJob(job.Type, batchName, job.Data, wf)
-		if err != nil {
-			logrus.Errorf("failed to create job for %s: %v", name, err)
-			continue
-		}
-		changed = true
-		jobName := wf.GetObjectMeta().GetName() + "-" + name
-		batches[name] = &v1alpha.BatchReference{"Job", jobName, ""}
-		if statuses == nil {
-			statuses = make(map[string]string)
+func (w *WorkflowOp) HandlePendingWf(orig *v1alpha.Workflow) error {
+	orig = orig.DeepCopy()
+	if err := w.ensureFinalizer(orig); err != nil {
+		logrus.Errorf("failed to add finalizer to workflow %s: %v", orig.Name, err)
+		return err
+	}
+	if err := w.applyWorkflowTemplate(orig); err != nil {
+		if _, invalid := err.(*invalidTemplateError); !invalid {
+			logrus.Errorf("failed to create workflow %s from its template: %v", orig.Name, err)
+			return err
 		}
-		statuses[name] = "working"
+		logrus.Errorf("workflow %s rejected: %v", orig.Name, err)
+		wf := orig.DeepCopy()
+		SetCondition(&wf.Status, v1alpha.WorkflowAdmitted, corev1.ConditionFalse, v1alpha.ReasonInvalidTemplate, err.Error())
+		return w.FailWorkflow(v1alpha.ReasonInvalidTemplate, err.Error(), orig, wf)
+	}
+	wf := orig.DeepCopy()
+	wf.Status.WorkflowTemplate = workflowTemplateStatus(wf)
+	if _, err := ResolveParameters(wf); err != nil {
+		logrus.Errorf("workflow %s rejected: %v", wf.Name, err)
+		SetCondition(&wf.Status, v1alpha.WorkflowAdmitted, corev1.ConditionFalse, v1alpha.ReasonInvalidArguments, err.Error())
+		return w.FailWorkflow(v1alpha.ReasonInvalidArguments, err.Error(), orig, wf)
+	}
+	jobs, err := SortJobs(WorkflowJobs(wf))
+	if err == nil {
+		err = validateAttemptNames(jobs, wf.Inputs.RetryStrategy)
+	}
+	if err != nil {
+		logrus.Errorf("workflow %s rejected: %v", wf.Name, err)
+		SetCondition(&wf.Status, v1alpha.WorkflowAdmitted, corev1.ConditionFalse, v1alpha.ReasonInvalidJobGraph, err.Error())
+		return w.FailWorkflow(v1alpha.ReasonInvalidJobGraph, err.Error(), orig, wf)
 	}
-	if changed {
-		updateErr = w.UpdateWorkflow(batches, statuses, "", wf)
-	} else if len(jobs) == len(batches) {
-		updateErr = w.UpdateWorkflow(nil, nil, "working", wf)
+	migrateJobBatch(wf)
+	now := metav1.Now()
+	wf.Status.StartedAt = &now
+	wf.Status.Status = v1alpha.WorkflowWorking
+	SetCondition(&wf.Status, v1alpha.WorkflowAdmitted, corev1.ConditionTrue, v1alpha.ReasonAdmitted, "")
+	setSuspended(wf)
+	expanded, _ := expandJobs(jobs, wf)
+	if !wf.Spec.Suspend {
+		w.ScheduleJobs(expanded, wf)
 	}
+	aggregateFanOut(jobs, wf)
+	updateErr := w.UpdateWorkflow(orig, wf)
 	if updateErr != nil {
 		logrus.Errorf("Update workflow error %v", updateErr)
 	}
 	return updateErr
 }
 
-func (w *WorkflowOp) HandleWorkingWf(wf *v1alpha.Workflow) error {
-	statuses := wf.Status.JobStatus
-	done := true
-	ok := "ok"
-	for _, status := range statuses {
-		if status != "ok" && status != "failed" {
+func (w *WorkflowOp) HandleWorkingWf(orig *v1alpha.Workflow) error {
+	wf := orig.DeepCopy()
+	jobs, err := SortJobs(WorkflowJobs(wf))
+	if err != nil {
+		logrus.Errorf("workflow %s has an invalid job graph: %v", wf.Name, err)
+		return w.FailWorkflow(v1alpha.ReasonInvalidJobGraph, err.Error(), orig, wf)
+	}
+	changed := migrateJobBatch(wf)
+	expanded, expandedChanged := expandJobs(jobs, wf)
+	timedOut, timeout := w.EnforceDeadlines(expanded, wf, time.Now())
+	if timeout != "" {
+		logrus.Printf("workflow %s timed out: %s", wf.Name, timeout)
+		return w.FailWorkflow(v1alpha.ReasonDeadlineExceeded, timeout, orig, wf)
+	}
+	changed = setSuspended(wf) || changed || expandedChanged || timedOut
+	if !wf.Spec.Suspend && w.ScheduleJobs(expanded, wf) {
+		changed = true
+	}
+	if aggregateFanOut(jobs, wf) {
+		changed = true
+	}
+	var failed []string
+	for _, job := range jobs {
+		status := wf.Status.JobStatus[job.Name]
+		if !status.Finished() {
 			// there are jobs not finished
-			done = false
-			break
+			if changed {
+				return w.UpdateWorkflow(orig, wf)
+			}
+			return nil
 		}
-		if status == "failed" {
-			ok = "failed"
+		if status != v1alpha.JobOK && status != v1alpha.JobSkipped {
+			failed = append(failed, job.Name)
 		}
 	}
-	if done {
-		updateErr := w.UpdateWorkflow(nil, nil, ok, wf)
-		if updateErr != nil {
-			logrus.Errorf("failed to update workflow %v", updateErr)
-			return updateErr
+	if len(failed) > 0 {
+		return w.FailWorkflow(v1alpha.ReasonJobFailed, "jobs did not succeed: "+strings.Join(failed, ", "), orig, wf)
+	}
+	wf.Status.Status = v1alpha.WorkflowOK
+	SetCondition(&wf.Status, v1alpha.WorkflowCompleted, corev1.ConditionTrue, v1alpha.ReasonJobsSucceeded, "")
+	updateErr := w.UpdateWorkflow(orig, wf)
+	if updateErr != nil {
+		logrus.Errorf("failed to update workflow %v", updateErr)
+	}
+	return updateErr
+}
+
+// FailWorkflow moves wf, a modified copy of orig, to "failed", recording why
+// in its Failed condition.
+func (w *WorkflowOp) FailWorkflow(reason, message string, orig, wf *v1alpha.Workflow) error {
+	wf.Status.Status = v1alpha.WorkflowFailed
+	SetCondition(&wf.Status, v1alpha.WorkflowFailedCondition, corev1.ConditionTrue, reason, message)
+	SetCondition(&wf.Status, v1alpha.WorkflowCompleted, corev1.ConditionTrue, reason, message)
+	updateErr := w.UpdateWorkflow(orig, wf)
+	if updateErr != nil {
+		logrus.Errorf("failed to update workflow %v", updateErr)
+	}
+	return updateErr
+}
+
+// ScheduleJobs creates the batch Job of every job whose upstream jobs have all
+// finished with "ok", and marks as "skipped" every job with a failed or
+// skipped upstream job. Ready jobs over their organization's quota are marked
+// "queued" and created on a later pass once admitted. jobs must be in
+// dependency order. wf is updated in place; the result reports whether
+// anything changed.
+func (w *WorkflowOp) ScheduleJobs(jobs []v1alpha.Job, wf *v1alpha.Workflow) bool {
+	batches := wf.Status.JobBatch
+	statuses := wf.Status.JobStatus
+	changed := false
+	now := time.Now()
+	var usage *quota.Usage
+	// The running children of capped fan-out jobs are counted once per pass
+	// and kept up to date as statuses change.
+	running := newFanOutRunning(wf)
+	setStatus := func(name string, phase v1alpha.JobPhase) {
+		running.set(name, statuses[name], phase)
+		statuses[name] = phase
+	}
+	fail := func(name string, batch *v1alpha.BatchReference, message string) {
+		logrus.Errorf("job %s failed: %s", name, message)
+		if batch == nil {
+			batch = &v1alpha.BatchReference{Kind: "Job"}
 		}
+		finished := metav1.NewTime(now)
+		batch.Message = message
+		batch.FinishedAt = &finished
+		batches[name] = batch
+		setStatus(name, v1alpha.JobFailed)
+		changed = true
 	}
-	return nil
+	for _, job := range jobs {
+		name := job.Name
+		batch := batches[name]
+		retrying := statuses[name] == v1alpha.JobRetrying
+		queued := statuses[name] == v1alpha.JobQueued
+		if (batch != nil && !retrying && !queued) || statuses[name] == v1alpha.JobSkipped {
+			logrus.Printf("%s job %s is in status %s", job.Type, name, statuses[name])
+			continue
+		}
+		if retrying && batch.RetryAfter != nil && now.Before(batch.RetryAfter.Time) {
+			continue
+		}
+		ready, blocked := upstreamState(job, &wf.Status)
+		if blocked {
+			logrus.Printf("%s job %s skipped because an upstream job did not succeed", job.Type, name)
+			setStatus(name, v1alpha.JobSkipped)
+			changed = true
+			continue
+		}
+		if !ready {
+			continue
+		}
+		if job.When != "" {
+			run, err := evalWhen(job, wf)
+			if err != nil {
+				fail(name, batch, fmt.Sprintf("when %s: %v", job.When, err))
+				continue
+			}
+			if !run {
+				logrus.Printf("%s job %s skipped because %s is false", job.Type, name, job.When)
+				if batch == nil {
+					batch = &v1alpha.BatchReference{Kind: "Job"}
+				}
+				batch.Reason = v1alpha.ReasonConditionFalse
+				batch.Message = fmt.Sprintf("when %s is false", job.When)
+				batches[name] = batch
+				setStatus(name, v1alpha.JobSkipped)
+				changed = true
+				continue
+			}
+		}
+		if running.reached(name) {
+			continue
+		}
+		data, err := resolveOutputs(job.Data, wf)
+		if err != nil {
+			fail(name, batch, err.Error())
+			continue
+		}
+		job.Data = data
+		if usage == nil {
+			current, err := w.jobUsage()
+			if err != nil {
+				logrus.Errorf("failed to list jobs: %v", err)
+				return changed
+			}
+			usage = &current
+		}
+		ticket := quotaTicket(job, wf)
+		if !w.quota.Admit(ticket, *usage) {
+			if !queued {
+				logrus.Printf("%s job %s of organization %q queued by quota", job.Type, name, ticket.Organization)
+				setStatus(name, v1alpha.JobQueued)
+				changed = true
+			}
+			continue
+		}
+		if batch == nil {
+			batch = &v1alpha.BatchReference{Kind: "Job"}
+		}
+		attempt := len(batch.Attempts)
+		batchName := attemptName(wf.GetObjectMeta().GetName()+"-"+name, attempt)
+		err = w.CreateJob(job, batchName, wf)
+		if _, taken := err.(*batchNameTakenError); taken || template.IsUnknownJobType(err) {
+			fail(name, batch, err.Error())
+			w.recordCreated(wf, name, attempt, batchName, v1alpha.JobFailed, job)
+			continue
+		}
+		if err != nil {
+			logrus.Errorf("failed to create job for %s: %v", name, err)
+			continue
+		}
+		changed = true
+		usage.Add(ticket.Organization, ticket.Type)
+		started := metav1.NewTime(now)
+		batch.Name = batchName
+		batch.RetryAfter = nil
+		batch.StartedAt = &started
+		batches[name] = batch
+		setStatus(name, v1alpha.JobWorking)
+		w.recordCreated(wf, name, attempt, batchName, v1alpha.JobWorking, job)
+	}
+	return changed
 }
 
 func (w *WorkflowOp) HandleJob(job *batchv1.Job) error {
 	logrus.Printf("Handle job %v", job.GetObjectMeta().GetName())
-	owner := job.GetOwnerReferences()[0]
-	if owner.Kind != "Workflow" {
+	if w.jobs != nil {
+		cached, err := w.jobs.Get(job.Namespace, job.Name)
+		if err != nil {
+			logrus.Errorf("failed to get job %s from cache: %v", job.Name, err)
+		} else if cached != nil {
+			job = cached
+		}
+	}
+	owners := job.GetOwnerReferences()
+	if len(owners) == 0 || owners[0].Kind != "Workflow" {
 		return nil
 	}
+	owner := owners[0]
 	wfname := owner.Name
-	workflow, err := w.GetWorkflowByName(wfname, job.Namespace)
+	orig, err := w.GetWorkflowByName(wfname, job.Namespace)
 	if err != nil {
 		logrus.Errorf("could not get owner reference workflow %v", err)
 		return err
 	}
-	status := workflow.Status.JobStatus[job.Name]
-	if status == "ok" || status == "failed" {
+	workflow := orig.DeepCopy()
+	migrateJobBatch(workflow)
+	updateName := job.Labels[template.JobLabel]
+	if updateName == "" {
+		updateName = strings.TrimPrefix(job.Name, workflow.Name+"-")
+	}
+	statuses := workflow.Status.JobStatus
+	batch := workflow.Status.JobBatch[updateName]
+	if batch == nil || batch.Name != job.Name {
+		logrus.Printf("job %s is not the current attempt of a job of workflow %s", job.Name, workflow.Name)
 		return nil
 	}
-	finished := job.Status.Succeeded == 1 || job.Status.Failed == 1
-	if finished {
-		statuses := workflow.Status.JobStatus
-		batches := workflow.Spec.JobBatch
-		prefix := workflow.Name + "-"
-		updateName := strings.TrimPrefix(job.Name, prefix)
-		logs := "hello world" //w.GetJobLogs(job)
-		if job.Status.Succeeded == 1 {
-			statuses[updateName] = "ok"
-		} else {
-			statuses[updateName] = "failed"
-		}
-		batches[updateName].Logs = logs
-		err := w.UpdateWorkflow(nil, statuses, "", workflow)
-		if err != nil {
-			logrus.Errorf("Update workflow error %v... ", err)
+	if statuses[updateName] != v1alpha.JobWorking {
+		return nil
+	}
+	finished, succeeded := jobFinished(job)
+	if !finished {
+		return nil
+	}
+	pod, err := w.GetJobPod(job)
+	if err != nil {
+		logrus.Errorf("failed to get pod of job %s: %v", job.Name, err)
+	}
+	logs, archive, err := w.GetJobLogs(job, pod)
+	if err != nil {
+		logrus.Errorf("failed to get logs of job %s: %v", job.Name, err)
+	}
+	if archive != nil {
+		batch.LogURL = archive.URL
+		batch.LogSize = archive.Size
+		batch.LogChecksum = archive.Checksum
+	}
+	batch.Logs = logs
+	attempt := v1alpha.JobAttempt{
+		Name:       job.Name,
+		Status:     v1alpha.JobOK,
+		FinishedAt: metav1.Now(),
+	}
+	statuses[updateName] = v1alpha.JobOK
+	if succeeded {
+		batch.Result = terminationMessage(pod)
+		batch.Outputs = parseOutputs(batch.Result)
+	} else {
+		attempt.Status = v1alpha.JobFailed
+		attempt.Reason = FailureReason(job, pod)
+		statuses[updateName] = v1alpha.JobFailed
+		strategy := retryStrategyFor(updateName, workflow)
+		if retries := len(batch.Attempts); shouldRetry(strategy, retries, attempt.Reason) {
+			retryAfter := metav1.NewTime(attempt.FinishedAt.Add(retryDelay(strategy, retries+1)))
+			batch.RetryAfter = &retryAfter
+			statuses[updateName] = v1alpha.JobRetrying
+			logrus.Printf("job %s failed with %s, retrying after %v", job.Name, attempt.Reason, retryAfter)
 		}
 	}
+	batch.Attempts = append(batch.Attempts, attempt)
+	if statuses[updateName].Finished() {
+		batch.FinishedAt = &attempt.FinishedAt
+	}
+	err = w.UpdateWorkflow(orig, workflow)
+	if err != nil {
+		logrus.Errorf("Update workflow error %v... ", err)
+		return err
+	}
+	w.recordFinished(workflow, updateName, batch, attempt.Status, logs)
 	return nil
 }
 
-func (w *WorkflowOp) CreateJob(jobType, jobName, jobData string, o *v1alpha.Workflow) error {
-	jobTemplate := w.GetJobTemplate(jobName, jobName, jobData, o)
-	jl, err := w.provider.ListJobs()
+func (w *WorkflowOp) CreateJob(job v1alpha.Job, jobName string, o *v1alpha.Workflow) error {
+	jobTemplate, err := w.GetJobTemplate(job, jobName, o)
 	if err != nil {
-		logrus.Errorf("failed to list jobs with %v", err)
-	}
-	logrus.Printf("current  number of jobs is %d ", len(jl.Items))
-	if length := len(jl.Items); length > 1000 {
-		return errors.New("job number has limits")
+		return err
 	}
 	createJobErr := w.provider.Create(jobTemplate)
-	if createJobErr != nil && !kubeerr.IsAlreadyExists(createJobErr) {
+	if kubeerr.IsAlreadyExists(createJobErr) {
+		// The Job was created by an earlier pass, unless it belongs to
+		// another job.
+		return w.checkExistingJob(jobTemplate)
+	}
+	if createJobErr != nil {
 		logrus.Errorf("failed to create job for %s: %v", jobName, createJobErr)
 		return createJobErr
 	}
+	if w.jobs != nil {
+		// The cache may not see the Job before the next quota check.
+		w.jobs.Created(jobTemplate)
+	}
 	return nil
 }
 
-func (w *WorkflowOp) GetJobLogs(job *batchv1.Job) string {
-	logrus.Println("GET JOBS LOGS .............")
+// batchNameTakenError is returned by CreateJob when the batch Job name of a
+// job is taken by a Job of another job or workflow.
+type batchNameTakenError struct {
+	name string
+}
 
-	podName := job.Spec.Template.ObjectMeta.Name
-	logrus.Printf("%v", job.Spec.Template.GetObjectMeta())
-	logrus.Printf("POD NAME IS .......... %s", podName)
-	pod, err := w.GetPodByName(podName, job.Namespace)
-	if err != nil {
-		logrus.Errorf("failed to get job pod %s", job.Name)
-	}
-	logs := w.GetLogFromPod(pod)
-	return logs
+func (e *batchNameTakenError) Error() string {
+	return fmt.Sprintf("batch Job %s already exists for another job", e.name)
 }
 
-func (w *WorkflowOp) GetLogFromPod(pod *corev1.Pod) string {
-	client := w.provider.GetKubeClient()
-	logOptions := &corev1.PodLogOptions{}
-	req := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOptions)
-	rc, err := req.Stream()
-	if err != nil {
-		logrus.Errorf("get log error: %v", err)
+// checkExistingJob returns a batchNameTakenError unless the existing Job
+// named like expected was created for the same workflow job.
+func (w *WorkflowOp) checkExistingJob(expected *batchv1.Job) error {
+	existing := &batchv1.Job{
+		TypeMeta:   metav1.TypeMeta{Kind: "Job", APIVersion: "batch/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: expected.Name, Namespace: expected.Namespace},
+	}
+	if err := w.provider.Get(existing); err != nil {
+		return err
 	}
-	defer rc.Close()
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(rc)
-	return buf.String()
+	for _, label := range []string{template.WorkflowLabel, template.JobLabel} {
+		if existing.Labels[label] != expected.Labels[label] {
+			return &batchNameTakenError{name: expected.Name}
+		}
+	}
+	return nil
 }
 
 func (w *WorkflowOp) GetWorkflowByName(name, namspace string) (*v1alpha.Workflow, error) {
@@ -205,74 +463,44 @@ func (w *WorkflowOp) GetWorkflowByName(name, namspace string) (*v1alpha.Workflow
 		},
 	}
 	err := w.provider.Get(workflow)
+	v1alpha.SetObjectDefaults_Workflow(workflow)
 	return workflow, err
 }
 
-func (w *WorkflowOp) UpdateWorkflow(batchReferences map[string]*v1alpha.BatchReference, batchStatus map[string]string, status string, wf *v1alpha.Workflow) error {
-	uploadO := wf.DeepCopy()
-	if batchReferences != nil {
-		uploadO.Spec.JobBatch = batchReferences
-	}
-	if batchStatus != nil {
-		uploadO.Status.JobStatus = batchStatus
+func (w *WorkflowOp) GetJobTemplate(job v1alpha.Job, jobName string, o *v1alpha.Workflow) (*batchv1.Job, error) {
+	spec, err := w.registry.Get(job.Type)
+	if err != nil {
+		return nil, err
 	}
-	if status != "" {
-		uploadO.Status.Status = status
+	if params, err := ResolveParameters(o); err == nil && len(params) > 0 && len(spec.Env) > 0 {
+		env := make([]corev1.EnvVar, 0, len(spec.Env))
+		for _, e := range spec.Env {
+			e.Value = substituteParameters(e.Value, params)
+			env = append(env, e)
+		}
+		spec.Env = env
 	}
-	updateErr := w.provider.Update(uploadO)
-	return updateErr
+	return template.GetJobTemplate(spec, job, jobName, o), nil
 }
 
-func (w *WorkflowOp) GetJobTemplate(jobType, jobName, jobData string, o *v1alpha.Workflow) *batchv1.Job {
-	labels := map[string]string{
-		"name": jobName,
+// HandleConfigMap reloads the job type registry or the quota policy when
+// their ConfigMap changes. Other ConfigMaps are ignored.
+func (w *WorkflowOp) HandleConfigMap(cm *corev1.ConfigMap) error {
+	if cm.Name == w.quotaConfigMap {
+		if err := w.quota.Load(cm); err != nil {
+			logrus.Errorf("failed to load quota policy from %s: %v", cm.Name, err)
+			return err
+		}
+		logrus.Printf("loaded quota policy from %s", cm.Name)
+		return nil
 	}
-	return &batchv1.Job{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "Job",
-			APIVersion: "batch/v1",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      jobName,
-			Namespace: "default",
-			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(o, schema.GroupVersionKind{
-					Group:   v1alpha.SchemeGroupVersion.Group,
-					Version: v1alpha.SchemeGroupVersion.Version,
-					Kind:    "Workflow",
-				}),
-			},
-			Labels: labels,
-		},
-		Spec: batchv1.JobSpec{
-			Template: corev1.PodTemplateSpec{
-				Spec: corev1.PodSpec{
-					RestartPolicy: "Never",
-					Containers: []corev1.Container{
-						{
-							Name:    "pi",
-							Image:   "perl",
-							Command: []string{"perl", "-Mbignum=bpi", "-wle", "print bpi(2000)"},
-						},
-					},
-				},
-			},
-		},
+	if cm.Name != w.jobTypesConfigMap {
+		return nil
 	}
-}
-
-func (w *WorkflowOp) GetPodByName(name, namespace string) (*corev1.Pod, error) {
-	pod := &corev1.Pod{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "Pod",
-			APIVersion: "v1",
-		},
+	if err := w.registry.Load(cm); err != nil {
+		logrus.Errorf("failed to load job types from %s: %v", cm.Name, err)
+		return err
 	}
-	err := w.provider.Get(pod)
-	return pod, err
-}
-
-func (w *WorkflowOp) CleanupWf(workflow *v1alpha.Workflow) error {
-	err := w.provider.Delete(workflow)
-	return err
+	logrus.Printf("loaded job types from %s", cm.Name)
+	return nil
 }
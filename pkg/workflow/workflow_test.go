@@ -0,0 +1,79 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/kube"
+	"github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha"
+	template "github.com/Ziyang2go/workflowop/pkg/templates"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// jobProvider stores the batch Jobs created, keyed by name. The other
+// Provider methods are not implemented.
+type jobProvider struct {
+	kube.Provider
+	jobs map[string]*batchv1.Job
+}
+
+func (p *jobProvider) Create(object runtime.Object) error {
+	job := object.(*batchv1.Job)
+	if _, found := p.jobs[job.Name]; found {
+		return kubeerr.NewAlreadyExists(schema.GroupResource{Group: "batch", Resource: "jobs"}, job.Name)
+	}
+	p.jobs[job.Name] = job.DeepCopy()
+	return nil
+}
+
+func (p *jobProvider) Get(object runtime.Object) error {
+	job := object.(*batchv1.Job)
+	stored, found := p.jobs[job.Name]
+	if !found {
+		return kubeerr.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, job.Name)
+	}
+	stored.DeepCopyInto(job)
+	return nil
+}
+
+func TestCreateJobExisting(t *testing.T) {
+	registry := template.NewRegistry()
+	if err := registry.Load(&corev1.ConfigMap{Data: map[string]string{"render": "image: render"}}); err != nil {
+		t.Fatal(err)
+	}
+	wf := &v1alpha.Workflow{ObjectMeta: metav1.ObjectMeta{Name: "wf", Namespace: "ns"}}
+	labels := func(workflow, job string) map[string]string {
+		return map[string]string{template.WorkflowLabel: workflow, template.JobLabel: job}
+	}
+	tests := []struct {
+		name      string
+		existing  map[string]string
+		wantTaken bool
+	}{
+		{name: "new"},
+		{name: "created before", existing: labels("wf", "a")},
+		{name: "other job", existing: labels("wf", "a-1"), wantTaken: true},
+		{name: "other workflow", existing: labels("other", "a"), wantTaken: true},
+		{name: "unlabelled", existing: map[string]string{}, wantTaken: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &jobProvider{jobs: make(map[string]*batchv1.Job)}
+			if tt.existing != nil {
+				p.jobs["wf-a-1"] = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "wf-a-1", Namespace: "ns", Labels: tt.existing}}
+			}
+			w := &WorkflowOp{provider: p, registry: registry}
+			err := w.CreateJob(v1alpha.Job{Name: "a", Type: "render"}, "wf-a-1", wf)
+			if _, taken := err.(*batchNameTakenError); taken != tt.wantTaken || (err != nil && !taken) {
+				t.Fatalf("CreateJob() error = %v, want name taken %v", err, tt.wantTaken)
+			}
+			if job := p.jobs["wf-a-1"]; job == nil {
+				t.Error("CreateJob() did not create the Job")
+			}
+		})
+	}
+}
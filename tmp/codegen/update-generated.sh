@@ -4,9 +4,49 @@ set -o errexit
 set -o nounset
 set -o pipefail
 
+# The typed clientset, listers and informers go to pkg/client.
 vendor/k8s.io/code-generator/generate-groups.sh \
-deepcopy \
-github.com/Ziyang2go/workflowop/pkg/generated \
+deepcopy,lister,informer \
+github.com/Ziyang2go/workflowop/pkg/client \
 github.com/Ziyang2go/workflowop/pkg/apis \
-threekit:v1alpha \
+threekit:v1alpha,v1beta1 \
 --go-header-file "./tmp/codegen/boilerplate.go.txt"
+
+go install ./vendor/k8s.io/code-generator/cmd/{client-gen,defaulter-gen,openapi-gen}
+
+# The fake clientset needs k8s.io/client-go/testing and
+# k8s.io/client-go/discovery/fake. Run with FAKE_CLIENTSET=true once dep
+# ensure vendored them.
+${GOPATH}/bin/client-gen \
+--clientset-name versioned \
+--input-base "" \
+--input github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha,github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1beta1 \
+--output-package github.com/Ziyang2go/workflowop/pkg/client/clientset \
+--fake-clientset="${FAKE_CLIENTSET:-false}" \
+--go-header-file "./tmp/codegen/boilerplate.go.txt"
+
+# client-gen lower cases the import path of the repository, so the typed
+# clients end up under github.com/ziyang2go and the imports refer to it.
+lowercase="${GOPATH}/src/github.com/ziyang2go/workflowop"
+if [ -d "${lowercase}" ] && [ ! "${lowercase}" -ef . ]; then
+  cp -r "${lowercase}/pkg/client/." pkg/client/
+  rm -rf "${GOPATH}/src/github.com/ziyang2go"
+fi
+find pkg/client -name '*.go' -exec sed -i.bak 's|github.com/ziyang2go/|github.com/Ziyang2go/|g' {} +
+find pkg/client -name '*.go.bak' -delete
+
+${GOPATH}/bin/defaulter-gen \
+--input-dirs github.com/Ziyang2go/workflowop/pkg/apis/threekit/v1alpha \
+-O zz_generated.defaults \
+--go-header-file "./tmp/codegen/boilerplate.go.txt"
+
+for version in v1alpha v1beta1; do
+${GOPATH}/bin/openapi-gen \
+--input-dirs github.com/Ziyang2go/workflowop/pkg/apis/threekit/${version} \
+--output-package github.com/Ziyang2go/workflowop/pkg/apis/threekit/${version} \
+-O zz_generated.openapi \
+--go-header-file "./tmp/codegen/boilerplate.go.txt"
+done
+
+# Install the OpenAPI schema in the CRDs under deploy/.
+go run ./cmd/crd-schema